@@ -1,41 +1,68 @@
 package cmd
 
 import (
-	"goweather/internal/api"
+	"errors"
+	"time"
+
 	"goweather/internal/cache"
 	"goweather/internal/cli"
 	"goweather/internal/config"
 	"goweather/internal/log"
-	"goweather/internal/ui"
 
 	"github.com/spf13/cobra"
 )
 
+var bothTimeoutFlag time.Duration
+
 func init() {
 	cmd := &cobra.Command{
 		Use:   "both",
 		Short: "Display both current and hourly forecasts concurrently",
+		Long: `Display current conditions and the hourly forecast, fetching both in
+parallel. If only one of them can be fetched it is still shown, followed by a
+warning, and the command exits with code 7.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
 			cfg, _ := config.Load()
 			log.Init(verboseFlag)
 			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
 
-			theme := ui.GetTheme(colorFlag, map[bool]string{true: "on", false: "off"}[emojiFlag])
+			theme := newTheme(cmd, cfg)
+			renderer := newRenderer(cmd, cfg, theme)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			vars := resolveVariables(cfg)
 			c := cache.NewCache(cfg.CacheDuration)
-			coords, err := api.GetCoordinates(cityFlag)
+			coords, err := resolveLocation(ctx, client, loc, theme)
 			if err != nil {
-				log.Logger.Fatalw("Geocoding failed", "error", err)
+				exitWithError("geocoding failed", err)
+			}
+			key := loc.key
+			err = cli.RunBothMode(ctx, provider, u, vars, coords, c, &key, &hoursFlag, bothTimeoutFlag, renderer, theme, cfg)
+			switch {
+			case errors.Is(err, cli.ErrPartial):
+				exitPartialResults(err)
+			case err != nil:
+				exitWithError("fetch failed", err)
 			}
-			cli.RunBothMode(coords, c, &cityFlag, &hoursFlag, theme, cfg)
-
 		},
 	}
 
-	cmd.Flags().StringVarP(&cityFlag, "city", "c", "belgrade", "City name")
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
 	cmd.Flags().IntVar(&hoursFlag, "hours", 6, "Number of hours to display")
-	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme")
+	cmd.Flags().DurationVar(&bothTimeoutFlag, "timeout", 30*time.Second, "Deadline shared by the current and hourly fetches (0 for none)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
 	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
 	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+	addOutputFlag(cmd)
+	addScaleFlags(cmd)
+	addSparklineFlag(cmd)
 
 	rootCmd.AddCommand(cmd)
 }
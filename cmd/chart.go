@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"goweather/internal/api"
+	"goweather/internal/cache"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	chartVarFlag    string
+	chartHoursFlag  int
+	chartWidthFlag  int
+	chartHeightFlag int
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "chart",
+		Short: "Draw a line chart of an hourly series",
+		Long: `Draw a line chart of one hourly series: temperature, humidity, wind_speed,
+pressure or any variable accepted by --vars (e.g. uv_index).
+
+Charts use braille characters and the color theme; with --color none or a
+non-UTF-8 locale they are drawn in plain ASCII.`,
+		Example: `  goweather chart --city belgrade
+  goweather chart --city belgrade --var wind_speed --hours 72
+  goweather chart --city belgrade --var uv_index --width 120 --height 16`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
+
+			theme := newTheme(cmd, cfg)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			name, vars := chartVariables(chartVarFlag, nil)
+			days := api.DaysForHours(chartHoursFlag)
+			c := cache.NewCache(cfg.CacheDuration)
+			coords, err := resolveLocation(ctx, client, loc, theme)
+			if err != nil {
+				exitWithError("geocoding failed", err)
+			}
+			result, err := provider.Hourly(ctx, coords.Latitude, coords.Longitude, days, u, vars)
+			if err != nil {
+				exitWithError("fetch failed", err)
+			}
+			result.Location = coords.Label()
+			c.Set(fmt.Sprintf("%s_hourly_%d_%s_%s", loc.key, days, u.Key(), api.VariablesKey(vars)), result)
+			view := cli.NewHourlyView(result, chartHoursFlag, cfg)
+			if err := cli.PrintChart(os.Stdout, view, name, theme, chartWidthFlag, chartHeightFlag); err != nil {
+				exitWithError("output failed", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
+	cmd.Flags().IntVar(&chartHoursFlag, "hours", 48, "Number of hours to chart")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
+	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addChartFlags(cmd)
+
+	rootCmd.AddCommand(cmd)
+}
+
+// addChartFlags registers the series and size flags of a chart.
+func addChartFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&chartVarFlag, "var", "temperature", "Series to chart: temperature|humidity|wind_speed|pressure or a --vars variable")
+	cmd.Flags().IntVar(&chartWidthFlag, "width", 0, "Chart width in columns (default $COLUMNS or 80)")
+	cmd.Flags().IntVar(&chartHeightFlag, "height", 0, "Chart height in rows (default 12)")
+}
+
+// chartVariables resolves the charted series and adds it to the variables
+// to fetch when it is not part of every forecast.
+func chartVariables(series string, vars []string) (string, []string) {
+	name, base := cli.ChartVariable(series)
+	if !base {
+		var err error
+		if vars, err = api.NormalizeVariables(append(vars, name)); err != nil {
+			exitWithCode("invalid variable", err, exitConfig)
+		}
+	}
+	return name, vars
+}
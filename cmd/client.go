@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/metrics"
+)
+
+// newClient builds the API client a command shares between geocoding and
+// its weather provider. It must be called after log.Init.
+func newClient(cfg *config.Config) *api.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.API.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.API.ProxyURL)
+		if err != nil {
+			exitWithCode("invalid proxy_url", err, exitConfig)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	opts := []api.Option{
+		api.WithHTTPClient(&http.Client{Timeout: cfg.API.Timeout, Transport: transport}),
+		api.WithRetryPolicy(api.RetryPolicy{
+			MaxAttempts: cfg.API.Retry.MaxAttempts,
+			BaseDelay:   cfg.API.Retry.BaseDelay,
+			MaxDelay:    cfg.API.Retry.MaxDelay,
+			Jitter:      cfg.API.Retry.Jitter,
+		}),
+		api.WithBreakerPolicy(api.BreakerPolicy{
+			FailureThreshold: cfg.API.CircuitBreaker.FailureThreshold,
+			Cooldown:         cfg.API.CircuitBreaker.Cooldown,
+		}),
+		api.WithLogger(log.Logger),
+		api.WithGeocodeCache(api.NewGeocodeCache(cfg.Geocode.CacheTTL)),
+	}
+	if cfg.API.ForecastURL != "" {
+		opts = append(opts, api.WithForecastURL(cfg.API.ForecastURL))
+	}
+	if cfg.API.GeocodingURL != "" {
+		opts = append(opts, api.WithGeocodingURL(cfg.API.GeocodingURL))
+	}
+	if cfg.API.UserAgent != "" {
+		opts = append(opts, api.WithUserAgent(cfg.API.UserAgent))
+	}
+	return api.NewClient(opts...)
+}
+
+// newProvider builds the configured weather provider on top of client,
+// with the derived comfort metrics available as variables.
+func newProvider(cfg *config.Config, client *api.Client) api.Provider {
+	provider, err := api.NewProvider(cfg.Provider, client)
+	if err != nil {
+		exitWithCode("invalid provider", err, exitConfig)
+	}
+	return metrics.WithMetrics(provider)
+}
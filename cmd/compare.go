@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"goweather/internal/api"
+	"goweather/internal/cache"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/model"
+	"goweather/internal/ui"
+	"goweather/internal/units"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareHourlyFlag   bool
+	compareParallelFlag int
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "compare <city> <city>...",
+		Short: "Compare the weather in several locations side by side",
+		Long: `Geocodes and fetches every location concurrently and shows them side by side.
+Locations may be city names or saved locations. If some fail, the others are
+still shown with a warning.
+Examples:
+  goweather compare belgrade novi-sad zagreb
+  goweather compare home office --hourly --hours 12`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+
+			theme := newTheme(cmd, cfg)
+			renderer := newRenderer(cmd, cfg, theme)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			vars := resolveVariables(cfg)
+			c := cache.NewCache(cfg.CacheDuration)
+
+			results := compareLocations(ctx, args, cfg, client, provider, u, vars, c, theme)
+			if err := renderer.Compare(os.Stdout, cli.NewCompareView(results, hoursFlag, cfg)); err != nil {
+				exitWithError("output failed", err)
+			}
+			cli.PrintCompareFailures(results, theme)
+
+			var failed []error
+			for _, r := range results {
+				if r.Err != nil {
+					failed = append(failed, fmt.Errorf("%s: %w", r.Name, r.Err))
+				}
+			}
+			switch {
+			case len(failed) == len(results):
+				exitWithError("compare failed", results[0].Err)
+			case len(failed) > 0:
+				exitPartialResults(fmt.Errorf("%w: %w", cli.ErrPartial, errors.Join(failed...)))
+			}
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeCity(cmd, args, toComplete)
+		},
+	}
+
+	cmd.Flags().BoolVar(&compareHourlyFlag, "hourly", false, "Show an hourly temperature grid instead of current conditions")
+	cmd.Flags().IntVar(&hoursFlag, "hours", 6, "Number of hours to display with --hourly")
+	cmd.Flags().IntVarP(&compareParallelFlag, "parallel", "j", 4, "Maximum number of locations fetched at once")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
+	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
+	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+	addOutputFlag(cmd)
+
+	rootCmd.AddCommand(cmd)
+}
+
+// compareLocations geocodes and fetches every name with at most
+// --parallel requests in flight, reusing cached forecasts. Results keep the
+// order of names; failures are recorded per location.
+func compareLocations(ctx context.Context, names []string, cfg *config.Config, client *api.Client, provider api.Provider, u units.Units, vars []string, c *cache.Cache, theme ui.Theme) []cli.CompareResult {
+	results := make([]cli.CompareResult, len(names))
+	sem := make(chan struct{}, max(compareParallelFlag, 1))
+
+	// Ambiguous names prompt one at a time
+	var pickMu sync.Mutex
+	var choose func([]api.Coordinates) (int, error)
+	if ui.IsTerminal(os.Stdin) {
+		choose = func(candidates []api.Coordinates) (int, error) {
+			pickMu.Lock()
+			defer pickMu.Unlock()
+			return cli.PickLocation(candidates, theme)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = cli.CompareResult{Name: name, Err: ctx.Err()}
+				return
+			}
+			results[i] = compareOne(ctx, name, cfg, client, provider, u, vars, c, choose)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// compareOne fetches one location for compareLocations.
+func compareOne(ctx context.Context, name string, cfg *config.Config, client *api.Client, provider api.Provider, u units.Units, vars []string, c *cache.Cache, choose func([]api.Coordinates) (int, error)) cli.CompareResult {
+	res := cli.CompareResult{Name: name}
+	loc, err := lookupLocation(name, cfg)
+	if err != nil {
+		res.Err = fmt.Errorf("invalid location: %w", err)
+		return res
+	}
+	coords, err := loc.resolve(ctx, client, choose)
+	if err != nil {
+		res.Err = fmt.Errorf("geocoding failed: %w", err)
+		return res
+	}
+	res.Label = coords.Label()
+
+	if compareHourlyFlag {
+		days := api.DaysForHours(hoursFlag)
+		key := fmt.Sprintf("%s_hourly_%d_%s_%s", loc.key, days, u.Key(), api.VariablesKey(vars))
+		if data, ok := c.Get(key); ok {
+			res.Hourly = data.(*model.HourlyForecast)
+			return res
+		}
+		if res.Hourly, err = provider.Hourly(ctx, coords.Latitude, coords.Longitude, days, u, vars); err != nil {
+			res.Err = fmt.Errorf("fetch failed: %w", err)
+			return res
+		}
+		res.Hourly.Location = res.Label
+		c.Set(key, res.Hourly)
+		return res
+	}
+
+	key := fmt.Sprintf("%s_current_%s_%s", loc.key, u.Key(), api.VariablesKey(vars))
+	if data, ok := c.Get(key); ok {
+		res.Current = data.(*model.WeatherResponse)
+		return res
+	}
+	if res.Current, err = provider.Current(ctx, coords.Latitude, coords.Longitude, u, vars); err != nil {
+		res.Err = fmt.Errorf("fetch failed: %w", err)
+		return res
+	}
+	res.Current.Location = res.Label
+	c.Set(key, res.Current)
+	return res
+}
@@ -2,13 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"goweather/internal/api"
 	"goweather/internal/cache"
 	"goweather/internal/cli"
 	"goweather/internal/config"
 	"goweather/internal/log"
-	"goweather/internal/ui"
 
 	"github.com/spf13/cobra"
 )
@@ -25,29 +25,45 @@ func init() {
 		Use:   "current",
 		Short: "Display current weather for a city",
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
 			cfg, _ := config.Load()
 			log.Init(verboseFlag)
 			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
 
-			theme := ui.GetTheme(colorFlag, map[bool]string{true: "on", false: "off"}[emojiFlag])
+			theme := newTheme(cmd, cfg)
+			renderer := newRenderer(cmd, cfg, theme)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			vars := resolveVariables(cfg)
 			c := cache.NewCache(cfg.CacheDuration)
-			coords, err := api.GetCoordinates(cityFlag)
+			coords, err := resolveLocation(ctx, client, loc, theme)
 			if err != nil {
-				log.Logger.Fatalw("Geocoding failed", "error", err)
+				exitWithError("geocoding failed", err)
 			}
-			result, err := api.GetWeather(coords.Latitude, coords.Longitude)
+			result, err := provider.Current(ctx, coords.Latitude, coords.Longitude, u, vars)
 			if err != nil {
-				log.Logger.Fatalw("Fetch failed", "error", err)
+				exitWithError("fetch failed", err)
+			}
+			result.Location = coords.Label()
+			c.Set(fmt.Sprintf("%s_current_%s_%s", loc.key, u.Key(), api.VariablesKey(vars)), result)
+			if err := renderer.Current(os.Stdout, cli.NewCurrentView(result, cfg)); err != nil {
+				exitWithError("output failed", err)
 			}
-			c.Set(fmt.Sprintf("%s_current", cityFlag), result)
-			cli.PrintCurrent(result, theme)
 		},
 	}
 
-	cmd.Flags().StringVarP(&cityFlag, "city", "c", "belgrade", "City name")
-	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none")
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
 	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
 	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+	addOutputFlag(cmd)
+	addScaleFlags(cmd)
 
 	rootCmd.AddCommand(cmd)
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"goweather/internal/cache"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+
+	"github.com/spf13/cobra"
+)
+
+var daysFlag int
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "daily",
+		Short: "Display daily forecast for a city (up to 16 days)",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
+
+			theme := newTheme(cmd, cfg)
+			renderer := newRenderer(cmd, cfg, theme)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			c := cache.NewCache(cfg.CacheDuration)
+			coords, err := resolveLocation(ctx, client, loc, theme)
+			if err != nil {
+				exitWithError("geocoding failed", err)
+			}
+			result, err := provider.Daily(ctx, coords.Latitude, coords.Longitude, daysFlag, u)
+			if err != nil {
+				exitWithError("fetch failed", err)
+			}
+			result.Location = coords.Label()
+			c.Set(fmt.Sprintf("%s_daily_%d_%s", loc.key, daysFlag, u.Key()), result)
+			if err := renderer.Daily(os.Stdout, cli.NewDailyView(result, daysFlag, cfg)); err != nil {
+				exitWithError("output failed", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
+	cmd.Flags().IntVar(&daysFlag, "days", 7, "Number of days to display (1-16)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
+	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
+	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addOutputFlag(cmd)
+	addScaleFlags(cmd)
+
+	rootCmd.AddCommand(cmd)
+}
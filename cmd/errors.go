@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"goweather/internal/api"
+	"goweather/internal/cli"
+	"goweather/internal/log"
+)
+
+// Exit codes returned by every goweather command (documented in the README).
+const (
+	exitOK                  = 0
+	exitError               = 1   // unexpected failure
+	exitConfig              = 2   // invalid configuration or flags
+	exitLocationNotFound    = 3   // geocoding found no match
+	exitUpstreamUnavailable = 4   // weather service down, unreachable or circuit open
+	exitRateLimited         = 5   // weather service rate limited us
+	exitDecode              = 6   // weather service returned unexpected data
+	exitPartial             = 7   // some of the requested data could not be fetched
+	exitInterrupted         = 130 // cancelled with Ctrl-C
+)
+
+// exitCode maps an error from internal/api to a process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, cli.ErrPartial):
+		return exitPartial
+	case errors.Is(err, api.ErrLocationNotFound):
+		return exitLocationNotFound
+	case errors.Is(err, api.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, api.ErrUpstreamRejected):
+		return exitError // a permanent client error, retrying won't help
+	case errors.Is(err, api.ErrUpstreamUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return exitUpstreamUnavailable
+	case errors.Is(err, api.ErrDecode):
+		return exitDecode
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	default:
+		return exitError
+	}
+}
+
+// exitWithError logs err, prints a readable message to stderr and exits with
+// the code matching the error.
+func exitWithError(msg string, err error) {
+	exitWithCode(msg, err, exitCode(err))
+}
+
+// exitWithCode is exitWithError with an explicit exit code.
+func exitWithCode(msg string, err error, code int) {
+	if log.Logger != nil {
+		log.Logger.Errorw(msg, "error", err, "exit_code", code)
+	}
+	log.Sync()
+	fmt.Fprintf(os.Stderr, "goweather: %s: %v\n", msg, err)
+	os.Exit(code)
+}
+
+// exitPartialResults exits with exitPartial after partial output has been
+// printed. The output already carries a warning, so err is only logged.
+func exitPartialResults(err error) {
+	if log.Logger != nil {
+		log.Logger.Warnw("Partial results", "error", err, "exit_code", exitPartial)
+	}
+	log.Sync()
+	os.Exit(exitPartial)
+}
+
+// apiError is the JSON body returned by the HTTP API on failure.
+type apiError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// writeError maps an error from internal/api to an HTTP status and JSON body.
+func writeError(w http.ResponseWriter, msg string, err error) {
+	status, code := http.StatusInternalServerError, "internal_error"
+	var upErr *api.UpstreamError
+	switch {
+	case errors.Is(err, api.ErrLocationNotFound):
+		status, code = http.StatusNotFound, "location_not_found"
+	case errors.Is(err, api.ErrRateLimited):
+		status, code = http.StatusTooManyRequests, "rate_limited"
+		if errors.As(err, &upErr) && upErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", fmt.Sprintf("%.0f", upErr.RetryAfter.Seconds()))
+		}
+	case errors.Is(err, api.ErrUpstreamRejected):
+		status, code = http.StatusUnprocessableEntity, "upstream_rejected"
+	case errors.Is(err, api.ErrUpstreamUnavailable):
+		status, code = http.StatusBadGateway, "upstream_unavailable"
+	case errors.Is(err, api.ErrDecode):
+		status, code = http.StatusBadGateway, "upstream_decode_error"
+	case errors.Is(err, context.Canceled):
+		// Client went away; nobody is left to read the response
+		log.Logger.Debugw("Request cancelled by client", "error", err)
+		return
+	}
+	writeJSONError(w, status, code, msg+": "+err.Error())
+}
+
+// writeJSONError writes an apiError body with the given status.
+func writeJSONError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{Error: msg, Code: code})
+}
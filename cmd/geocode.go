@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var geocodeCountFlag int
+
+func init() {
+	geocodeCmd := &cobra.Command{
+		Use:   "geocode",
+		Short: "Look up places known to the geocoder",
+	}
+
+	searchCmd := &cobra.Command{
+		Use:   "search <name>",
+		Short: "List places matching a name",
+		Long: `Lists geocoding candidates with region, country, population and coordinates.
+Examples:
+  goweather geocode search springfield
+  goweather geocode search springfield --country US --admin1 illinois`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+
+			theme := ui.GetTheme(colorFlag, "on")
+			client := newClient(cfg)
+			name := strings.Join(args, " ")
+			opts := api.GeocodeOptions{Country: countryFlag, Admin1: admin1Flag}
+			candidates, err := client.SearchLocations(ctx, name, opts, geocodeCountFlag)
+			if err != nil {
+				exitWithError("geocoding failed", err)
+			}
+			if len(candidates) == 0 {
+				exitWithError("geocoding failed", api.ErrLocationNotFound)
+			}
+			cli.PrintLocations(os.Stdout, candidates, theme)
+		},
+	}
+
+	searchCmd.Flags().IntVarP(&geocodeCountFlag, "count", "n", api.DefaultGeocodeCount, "Maximum number of candidates (1-100)")
+	searchCmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none")
+	searchCmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addGeocodeFilterFlags(searchCmd)
+
+	importCmd := &cobra.Command{
+		Use:   "import <cities15000.txt|.zip>",
+		Short: "Build the offline geocoding index from a GeoNames dump",
+		Long: `Imports a GeoNames cities dump (https://download.geonames.org/export/dump/,
+e.g. cities15000.zip) into an offline index in the cache directory. Geocoding
+then searches this index first and only calls the API when it has no match.
+If admin1CodesASCII.txt is next to the dump, region codes become names;
+with countryInfo.txt, every country gets its name.
+Examples:
+  goweather geocode import cities15000.txt
+  goweather geocode import ~/Downloads/cities500.zip`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			log.Init(verboseFlag)
+			defer log.Sync()
+
+			places, err := api.ImportGeoNames(args[0])
+			if err != nil {
+				exitWithCode("import failed", err, exitConfig)
+			}
+			if err := api.SavePlaceIndex(places); err != nil {
+				exitWithError("import failed", err)
+			}
+			log.Logger.Infow("Imported GeoNames dump", "file", args[0], "places", len(places), "index", api.PlaceIndexPath())
+			fmt.Printf("Imported %d places into %s\n", len(places), api.PlaceIndexPath())
+		},
+	}
+	importCmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+
+	geocodeCmd.AddCommand(searchCmd, importCmd, newGeocodeCacheCmd())
+	rootCmd.AddCommand(geocodeCmd)
+}
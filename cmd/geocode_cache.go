@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeExpiredFlag bool
+	purgeOlderFlag   time.Duration
+)
+
+// newGeocodeCacheCmd builds `geocode cache` and its subcommands.
+func newGeocodeCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clean the geocode cache",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List remembered locations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			gc := openGeocodeCache()
+			entries, err := gc.Entries()
+			if err != nil {
+				exitWithError("reading geocode cache failed", err)
+			}
+			if len(entries) == 0 {
+				fmt.Printf("Geocode cache is empty (%s)\n", gc.Path())
+				return
+			}
+			cli.PrintGeocodeCache(os.Stdout, entries, gc.TTL(), ui.GetTheme(colorFlag, "on"))
+		},
+	}
+	listCmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none")
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the remembered location for a name",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			gc := openGeocodeCache()
+			key := api.GeocodeCacheKey(strings.Join(args, " "), api.GeocodeOptions{Country: countryFlag, Admin1: admin1Flag})
+			entries, err := gc.Entries()
+			if err != nil {
+				exitWithError("reading geocode cache failed", err)
+			}
+			for _, e := range entries {
+				if e.Key == key {
+					cli.PrintGeocodeCacheEntry(os.Stdout, e, gc.TTL())
+					return
+				}
+			}
+			exitWithError("show failed", fmt.Errorf("%w: %q is not in the geocode cache", api.ErrLocationNotFound, key))
+		},
+	}
+	addGeocodeFilterFlags(showCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>...",
+		Short: "Forget remembered locations",
+		Long: `Forgets remembered locations so the next lookup asks the geocoder again.
+Each argument is one name; use --country/--admin1 for entries saved with filters.
+Examples:
+  goweather geocode cache delete newyork
+  goweather geocode cache delete springfield --country US`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			gc := openGeocodeCache()
+			keys := make([]string, len(args))
+			for i, name := range args {
+				keys[i] = api.GeocodeCacheKey(name, api.GeocodeOptions{Country: countryFlag, Admin1: admin1Flag})
+			}
+			n, err := gc.Delete(keys...)
+			if err != nil {
+				exitWithError("delete failed", err)
+			}
+			fmt.Printf("Deleted %d of %d entries\n", n, len(keys))
+		},
+	}
+	addGeocodeFilterFlags(deleteCmd)
+
+	purgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove all (or only expired) remembered locations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			gc := openGeocodeCache()
+			maxAge := purgeOlderFlag
+			if purgeExpiredFlag && maxAge == 0 {
+				if maxAge = gc.TTL(); maxAge == 0 {
+					exitWithCode("purge failed", fmt.Errorf("--expired needs geocode.cache_ttl in config, or use --older-than"), exitConfig)
+				}
+			}
+			n, err := gc.Purge(maxAge)
+			if err != nil {
+				exitWithError("purge failed", err)
+			}
+			fmt.Printf("Removed %d entries\n", n)
+		},
+	}
+	purgeCmd.Flags().BoolVar(&purgeExpiredFlag, "expired", false, "Only remove entries older than geocode.cache_ttl")
+	purgeCmd.Flags().DurationVar(&purgeOlderFlag, "older-than", 0, "Only remove entries older than this, e.g. 720h")
+
+	cacheCmd.AddCommand(listCmd, showCmd, deleteCmd, purgeCmd)
+	return cacheCmd
+}
+
+// openGeocodeCache opens the geocode cache with the configured TTL.
+func openGeocodeCache() *api.GeocodeCache {
+	cfg, _ := config.Load()
+	log.Init(false)
+	return api.NewGeocodeCache(cfg.Geocode.CacheTTL)
+}
@@ -2,52 +2,95 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"goweather/internal/api"
 	"goweather/internal/cache"
 	"goweather/internal/cli"
 	"goweather/internal/config"
 	"goweather/internal/log"
-	"goweather/internal/ui"
 
 	"github.com/spf13/cobra"
 )
 
-var hoursFlag int
+var (
+	hoursFlag       int
+	hourlyDaysFlag  int
+	hourlyChartFlag bool
+)
 
 func init() {
 	cmd := &cobra.Command{
 		Use:   "hourly",
 		Short: "Display hourly forecast for a city",
 		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
 			cfg, _ := config.Load()
 			log.Init(verboseFlag)
 			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
+
+			// --days without --hours shows every hour of the requested days
+			if cmd.Flags().Changed("days") && !cmd.Flags().Changed("hours") {
+				hoursFlag = 0
+			}
+			days := hourlyDaysFlag
+			if days <= 0 {
+				days = api.DaysForHours(hoursFlag)
+			}
 
-			theme := ui.GetTheme(colorFlag, map[bool]string{true: "on", false: "off"}[emojiFlag])
+			theme := newTheme(cmd, cfg)
+			renderer := newRenderer(cmd, cfg, theme)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			vars := resolveVariables(cfg)
+			chartVar := ""
+			if hourlyChartFlag {
+				if !tableOutput() {
+					exitWithCode("invalid flags", fmt.Errorf("--chart only works with table output"), exitConfig)
+				}
+				chartVar, vars = chartVariables(chartVarFlag, vars)
+			}
 			c := cache.NewCache(cfg.CacheDuration)
-			coords, err := api.GetCoordinates(cityFlag)
+			coords, err := resolveLocation(ctx, client, loc, theme)
 			if err != nil {
-				log.Logger.Fatalw("Geocoding failed", "error", err)
+				exitWithError("geocoding failed", err)
 			}
-			result, err := api.GetHourly(coords.Latitude, coords.Longitude)
+			result, err := provider.Hourly(ctx, coords.Latitude, coords.Longitude, days, u, vars)
 			if err != nil {
-				log.Logger.Fatalw("Fetch failed", "error", err)
+				exitWithError("fetch failed", err)
+			}
+			result.Location = coords.Label()
+			key := fmt.Sprintf("%s_hourly_%d_%s_%s", loc.key, days, u.Key(), api.VariablesKey(vars))
+			c.Set(key, result)
+			view := cli.NewHourlyView(result, hoursFlag, cfg)
+			if err := renderer.Hourly(os.Stdout, view); err != nil {
+				exitWithError("output failed", err)
+			}
+			if hourlyChartFlag {
+				if err := cli.PrintChart(os.Stdout, view, chartVar, theme, chartWidthFlag, chartHeightFlag); err != nil {
+					exitWithError("output failed", err)
+				}
 			}
-			c.Set(fmt.Sprintf("%s_hourly", cityFlag), result)
-			c.BackgroundRefresh(fmt.Sprintf("%s_hourly", cityFlag), func() (any, error) {
-				return api.GetHourly(coords.Latitude, coords.Longitude)
-			})
-			cli.PrintHourly(result, theme, hoursFlag, cfg)
-
 		},
 	}
 
-	cmd.Flags().StringVarP(&cityFlag, "city", "c", "belgrade", "City name")
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
 	cmd.Flags().IntVar(&hoursFlag, "hours", 6, "Number of hours to display")
-	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme")
+	cmd.Flags().IntVar(&hourlyDaysFlag, "days", 0, "Number of forecast days to fetch, 1-16 (default: enough to cover --hours)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
 	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
 	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+	addOutputFlag(cmd)
+	addScaleFlags(cmd)
+	addSparklineFlag(cmd)
+	cmd.Flags().BoolVar(&hourlyChartFlag, "chart", false, "Draw a line chart of --var below the table")
+	addChartFlags(cmd)
 
 	rootCmd.AddCommand(cmd)
 }
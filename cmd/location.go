@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	countryFlag string
+	admin1Flag  string
+	latFlag     float64
+	lonFlag     float64
+)
+
+// addLocationFlags registers the geocoding filters and direct coordinates
+// shared by the weather commands, and completion for their --city flag.
+func addLocationFlags(cmd *cobra.Command) {
+	addGeocodeFilterFlags(cmd)
+	cmd.Flags().Float64Var(&latFlag, "lat", 0, "Latitude; with --lon skips geocoding")
+	cmd.Flags().Float64Var(&lonFlag, "lon", 0, "Longitude; with --lat skips geocoding")
+	cmd.MarkFlagsRequiredTogether("lat", "lon")
+	cmd.RegisterFlagCompletionFunc("city", completeCity)
+}
+
+// addGeocodeFilterFlags registers --country/--admin1.
+func addGeocodeFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&countryFlag, "country", "", "Only match places in this country (ISO code or name)")
+	cmd.Flags().StringVar(&admin1Flag, "admin1", "", "Only match places in this region (state, province, ...)")
+}
+
+// location is a place to fetch weather for: fixed coordinates, or a city
+// name to geocode.
+type location struct {
+	key    string // weather cache key
+	city   string
+	geo    api.GeocodeOptions
+	coords *api.Coordinates
+	label  string           // display label of a saved location
+	saved  *config.Location // set for saved locations
+}
+
+// cityLocation geocodes a city name with optional filters.
+func cityLocation(city, country, admin1 string) location {
+	return location{
+		key:  cityKey(city, country, admin1),
+		city: city,
+		geo:  api.GeocodeOptions{Country: country, Admin1: admin1},
+	}
+}
+
+// coordinatesLocation uses lat/lon as given, labelled with the nearest known place.
+func coordinatesLocation(lat, lon float64) location {
+	return location{key: coordinatesKey(lat, lon), coords: api.ReverseGeocode(lat, lon)}
+}
+
+// savedLocation resolves a location from the config's locations section.
+func savedLocation(l config.Location) (location, error) {
+	if err := l.Validate(); err != nil {
+		return location{}, err
+	}
+	var loc location
+	if l.HasCoordinates() {
+		if err := api.ValidateCoordinates(*l.Latitude, *l.Longitude); err != nil {
+			return location{}, err
+		}
+		loc = coordinatesLocation(*l.Latitude, *l.Longitude)
+	} else {
+		loc = cityLocation(l.City, l.Country, l.Admin1)
+	}
+	if l.Label != "" {
+		loc.label = l.Label
+		loc.key += "#" + l.Label
+	}
+	loc.saved = &l
+	return loc, nil
+}
+
+// lookupLocation resolves a name from the config's locations section, or
+// treats it as a city.
+func lookupLocation(name string, cfg *config.Config) (location, error) {
+	if _, saved, ok := cfg.Locations.Lookup(name); ok {
+		return savedLocation(saved)
+	}
+	return cityLocation(name, "", ""), nil
+}
+
+// resolve returns the coordinates, geocoding if needed. choose picks among
+// ambiguous candidates and may be nil.
+func (l location) resolve(ctx context.Context, client *api.Client, choose func([]api.Coordinates) (int, error)) (*api.Coordinates, error) {
+	coords := l.coords
+	if coords == nil {
+		geo := l.geo
+		geo.Choose = choose
+		var err error
+		coords, err = client.Geocode(ctx, l.city, geo)
+		if errors.Is(err, api.ErrLocationNotFound) && strings.Contains(l.city, "-") {
+			// Shell-friendly spellings such as novi-sad
+			coords, err = client.Geocode(ctx, strings.ReplaceAll(l.city, "-", " "), geo)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if l.label != "" {
+		c := *coords
+		c.Name, c.Admin1, c.Country = l.label, "", ""
+		coords = &c
+	}
+	return coords, nil
+}
+
+// selectLocation reads --lat/--lon or --city (falling back to the config's
+// city). A name from the config's locations section also makes that
+// location's time zone and units the defaults in cfg, so call it before
+// resolveUnits.
+func selectLocation(cmd *cobra.Command, cfg *config.Config) location {
+	if cmd.Flags().Changed("lat") && cmd.Flags().Changed("lon") {
+		if err := api.ValidateCoordinates(latFlag, lonFlag); err != nil {
+			exitWithCode("invalid coordinates", err, exitConfig)
+		}
+		return coordinatesLocation(latFlag, lonFlag)
+	}
+
+	city := cityFlag
+	if city == "" {
+		city = cfg.City
+	}
+	if countryFlag == "" && admin1Flag == "" {
+		if name, saved, ok := cfg.Locations.Lookup(city); ok {
+			loc, err := savedLocation(saved)
+			if err != nil {
+				exitWithCode(fmt.Sprintf("invalid location %q", name), err, exitConfig)
+			}
+			cfg.ApplyLocation(saved)
+			return loc
+		}
+	}
+	return cityLocation(city, countryFlag, admin1Flag)
+}
+
+// resolveLocation resolves loc. When several places match and stdin is a
+// terminal the user picks one; the choice is remembered in the geocode cache.
+func resolveLocation(ctx context.Context, client *api.Client, loc location, theme ui.Theme) (*api.Coordinates, error) {
+	var choose func([]api.Coordinates) (int, error)
+	if ui.IsTerminal(os.Stdin) {
+		choose = func(candidates []api.Coordinates) (int, error) {
+			return cli.PickLocation(candidates, theme)
+		}
+	}
+	return loc.resolve(ctx, client, choose)
+}
+
+// completeCity offers saved locations and previously geocoded names for --city.
+func completeCity(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _ := config.Load()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	prefix := strings.ToLower(toComplete)
+	seen := make(map[string]bool)
+	var out []string
+	add := func(name, desc string) {
+		if !seen[name] && strings.HasPrefix(strings.ToLower(name), prefix) {
+			seen[name] = true
+			out = append(out, name+"\t"+desc)
+		}
+	}
+	for _, name := range cfg.Locations.Names() {
+		desc := "saved location"
+		if l := cfg.Locations[name]; l.Label != "" {
+			desc = l.Label
+		}
+		add(name, desc)
+	}
+	if entries, err := api.NewGeocodeCache(0).Entries(); err == nil {
+		for _, e := range entries {
+			if !strings.Contains(e.Key, "|") {
+				add(e.Key, e.Label())
+			}
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cityKey identifies a city name and its geocoding filters in cache keys,
+// normalized like the geocode cache so "Belgrade" and "belgrade " share entries.
+func cityKey(city, country, admin1 string) string {
+	return api.GeocodeCacheKey(city, api.GeocodeOptions{Country: country, Admin1: admin1})
+}
+
+// coordinatesKey rounds to ~10 m so equivalent inputs share cache entries.
+func coordinatesKey(lat, lon float64) string {
+	return fmt.Sprintf("%.4f,%.4f", lat, lon)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/ui"
+	"goweather/internal/units"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	labelFlag    string
+	timezoneFlag string
+)
+
+func init() {
+	list := func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			exitWithCode("reading config failed", err, exitConfig)
+		}
+		if len(cfg.Locations) == 0 {
+			fmt.Printf("No saved locations in %s; add one with `goweather locations add`\n", config.Path)
+			return
+		}
+		cli.PrintSavedLocations(os.Stdout, cfg.Locations, ui.GetTheme(colorFlag, "on"))
+	}
+
+	locationsCmd := &cobra.Command{
+		Use:   "locations",
+		Short: "List, add and remove saved locations",
+		Long: `Saved locations live under "locations:" in config.yaml and can be used
+anywhere a city is accepted, e.g. --city home.
+Examples:
+  goweather locations add home --city belgrade --label Home
+  goweather locations add cabin --lat 44.05 --lon 19.93 --timezone Europe/Belgrade
+  goweather locations add office --city springfield --country US --admin1 illinois --units imperial
+  goweather current --city home
+  goweather locations remove cabin`,
+		Args: cobra.NoArgs,
+		Run:  list,
+	}
+	locationsCmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved locations",
+		Args:  cobra.NoArgs,
+		Run:   list,
+	}
+	listCmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none")
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Save a location (replacing one with the same name)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			loc := config.Location{
+				Label:    labelFlag,
+				City:     cityFlag,
+				Country:  countryFlag,
+				Admin1:   admin1Flag,
+				TimeZone: timezoneFlag,
+				Units: config.UnitsConfig{
+					System: unitsFlag,
+					Units: units.Units{
+						Temperature:   tempUnitFlag,
+						WindSpeed:     windUnitFlag,
+						Precipitation: precipUnitFlag,
+						Pressure:      pressureUnitFlag,
+					},
+				},
+			}
+			if cmd.Flags().Changed("lat") {
+				loc.Latitude, loc.Longitude = &latFlag, &lonFlag
+			}
+			if loc.City != "" && loc.HasCoordinates() {
+				exitWithCode("invalid location", fmt.Errorf("use either --city or --lat/--lon"), exitConfig)
+			}
+			if err := validateSavedLocation(loc); err != nil {
+				exitWithCode("invalid location", err, exitConfig)
+			}
+			if err := config.SaveLocation(args[0], loc); err != nil {
+				exitWithError("saving location failed", err)
+			}
+			fmt.Printf("Saved location %q in %s\n", args[0], config.Path)
+		},
+	}
+	addCmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name to geocode")
+	addCmd.Flags().StringVar(&labelFlag, "label", "", "Display label (default: the geocoded place)")
+	addCmd.Flags().StringVar(&timezoneFlag, "timezone", "", "Time zone for hourly output, e.g. Europe/Belgrade")
+	addLocationFlags(addCmd)
+	addUnitFlags(addCmd)
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <name>...",
+		Aliases: []string{"rm"},
+		Short:   "Remove saved locations",
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, _ := config.Load()
+			if cfg == nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return cfg.Locations.Names(), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, name := range args {
+				found, err := config.RemoveLocation(name)
+				if err != nil {
+					exitWithError("removing location failed", err)
+				}
+				if !found {
+					exitWithCode("removing location failed", fmt.Errorf("no saved location %q", name), exitConfig)
+				}
+				fmt.Printf("Removed location %q\n", name)
+			}
+		},
+	}
+
+	locationsCmd.AddCommand(listCmd, addCmd, removeCmd)
+	rootCmd.AddCommand(locationsCmd)
+}
+
+// validateSavedLocation checks everything config.Location.Validate cannot:
+// coordinate ranges, the time zone name and the units.
+func validateSavedLocation(l config.Location) error {
+	if _, err := savedLocation(l); err != nil {
+		return err
+	}
+	if l.TimeZone != "" {
+		if _, err := time.LoadLocation(l.TimeZone); err != nil {
+			return fmt.Errorf("time zone: %w", err)
+		}
+	}
+	u := units.Metric
+	if l.Units.System != "" {
+		var err error
+		if u, err = units.System(l.Units.System); err != nil {
+			return err
+		}
+	}
+	return u.With(l.Units.Units).Validate()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputFlag    string
+	formatFlag    string
+	waybarFlag    bool
+	sparklineFlag bool
+	scalesFlag    bool
+	legendFlag    bool
+)
+
+// addOutputFlag registers --output, --format and --waybar on commands that
+// render weather data.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFlag, "output", "o", cli.FormatTable, "Output format: "+strings.Join(cli.Formats, "|"))
+	cmd.Flags().StringVar(&formatFlag, "format", "", "Go template for the output, e.g. '{{.Location}} {{.Temp | round}}{{.Units.Temp}} {{.Icon}}'")
+	cmd.Flags().BoolVar(&waybarFlag, "waybar", false, "Print the JSON object a Waybar custom module expects")
+	cmd.MarkFlagsMutuallyExclusive("output", "format", "waybar")
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(cli.Formats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// newRenderer returns the renderer selected with --output, --format or --waybar.
+func newRenderer(cmd *cobra.Command, cfg *config.Config, theme ui.Theme) cli.Renderer {
+	switch {
+	case waybarFlag:
+		return cli.NewWaybarRenderer()
+	case formatFlag != "":
+		r, err := cli.NewTemplateRenderer(formatFlag)
+		if err != nil {
+			exitWithCode("invalid format template", err, exitConfig)
+		}
+		return r
+	}
+	if tableOutput() {
+		return cli.NewTableRenderer(theme, tableOptions(cmd, cfg))
+	}
+	r, err := cli.NewRenderer(outputFlag, theme)
+	if err != nil {
+		exitWithCode("invalid output format", err, exitConfig)
+	}
+	return r
+}
+
+// tableOutput reports whether the terminal table was selected.
+func tableOutput() bool {
+	return !waybarFlag && formatFlag == "" && strings.EqualFold(strings.TrimSpace(outputFlag), cli.FormatTable)
+}
+
+// addSparklineFlag registers --sparkline on commands with an hourly table.
+func addSparklineFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&sparklineFlag, "sparkline", false, "Add a trend row with a sparkline of each hourly column")
+}
+
+// addScaleFlags registers --scales and --legend on commands with tables.
+func addScaleFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&scalesFlag, "scales", true, "Color values by magnitude (default from config)")
+	cmd.Flags().BoolVar(&legendFlag, "legend", false, "Describe the color scales below each table (default from config)")
+}
+
+// tableOptions returns the table renderer options from the config and the
+// --sparkline, --scales and --legend flags. Scales in the config replace the
+// built-in ones of the same name.
+func tableOptions(cmd *cobra.Command, cfg *config.Config) cli.TableOptions {
+	opts := cli.TableOptions{
+		Sparkline: sparklineFlag,
+		Legend:    cfg.ColorScales.Legend,
+		Depth:     ui.DetectColorDepth(),
+	}
+	enabled := cfg.ColorScales.Enabled
+	if f := cmd.Flags().Lookup("scales"); f != nil && f.Changed {
+		enabled = scalesFlag
+	}
+	if f := cmd.Flags().Lookup("legend"); f != nil && f.Changed {
+		opts.Legend = legendFlag
+	}
+	if !enabled {
+		opts.Legend = false
+		return opts
+	}
+	opts.Scales = ui.DefaultScales()
+	for name, s := range cfg.ColorScales.Scales {
+		opts.Scales[name] = s
+	}
+	for name, s := range opts.Scales {
+		if err := s.Validate(); err != nil {
+			exitWithCode("invalid color scale", fmt.Errorf("%s: %w", name, err), exitConfig)
+		}
+	}
+	return opts
+}
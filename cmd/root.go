@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -14,11 +17,17 @@ var rootCmd = &cobra.Command{
 using the Open-Meteo public API. Example:
 
   goweather current --city belgrade
-  goweather hourly --city belgrade --hours 6`,
+  goweather hourly --city belgrade --hours 6
+  goweather daily --city belgrade --days 7`,
 }
 
+// Execute runs the root command with a context that is cancelled on
+// Ctrl-C/SIGTERM, so in-flight requests and retry backoffs stop promptly.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
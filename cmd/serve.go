@@ -5,17 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
 	"time"
 
 	"goweather/internal/api"
 	"goweather/internal/cache"
+	"goweather/internal/cli"
 	"goweather/internal/config"
 	"goweather/internal/log"
 	"goweather/internal/model"
+	"goweather/internal/units"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -33,7 +32,14 @@ Examples:
   goweather serve --port 8080
 Then open:
   http://localhost:8080/api/v1/current?city=belgrade
-  http://localhost:8080/api/v1/hourly?city=belgrade&hours=6`,
+  http://localhost:8080/api/v1/current?city=springfield&country=US&admin1=illinois
+  http://localhost:8080/api/v1/current?lat=45.33&lon=19.85
+  http://localhost:8080/api/v1/daily?city=home
+  http://localhost:8080/api/v1/hourly?city=belgrade&hours=6
+  http://localhost:8080/api/v1/hourly?city=belgrade&days=3
+  http://localhost:8080/api/v1/daily?city=belgrade&days=7
+  http://localhost:8080/api/v1/current?city=belgrade&units=imperial&wind_speed_unit=kn
+  http://localhost:8080/api/v1/hourly?city=belgrade&vars=apparent_temperature,uv_index`,
 		Run: runServer,
 	}
 
@@ -46,15 +52,20 @@ func runServer(cmd *cobra.Command, args []string) {
 	log.Init(cfg.Verbose)
 	defer log.Sync()
 
-	c := cache.NewCache(cfg.CacheDuration)
+	client := newClient(cfg)
+	s := &server{
+		cache:     cache.NewCache(cfg.CacheDuration),
+		client:    client,
+		provider:  newProvider(cfg, client),
+		units:     resolveUnits(cfg),
+		vars:      resolveVariables(cfg),
+		locations: cfg.Locations,
+	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/v1/current", func(w http.ResponseWriter, r *http.Request) {
-		handleCurrent(w, r, c)
-	})
-	mux.HandleFunc("/api/v1/hourly", func(w http.ResponseWriter, r *http.Request) {
-		handleHourly(w, r, c)
-	})
+	mux.HandleFunc("/api/v1/current", s.handleCurrent)
+	mux.HandleFunc("/api/v1/hourly", s.handleHourly)
+	mux.HandleFunc("/api/v1/daily", s.handleDaily)
 	mux.Handle("/metrics", promhttp.Handler())
 
 	addr := fmt.Sprintf(":%d", port)
@@ -67,16 +78,14 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	// Start server in goroutine
 	go func() {
-		log.Logger.Infow("Starting HTTP server", "port", port)
+		log.Logger.Infow("Starting HTTP server", "port", port, "provider", s.provider.Name())
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Logger.Fatalw("Server failed", "error", err)
 		}
 	}()
 
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
+	// Graceful shutdown once Ctrl-C/SIGTERM cancels the command context
+	<-cmd.Context().Done()
 
 	log.Logger.Infow("Shutdown signal received, shutting down server...")
 
@@ -90,70 +99,252 @@ func runServer(cmd *cobra.Command, args []string) {
 	}
 }
 
-func handleCurrent(w http.ResponseWriter, r *http.Request, c *cache.Cache) {
-	city := r.URL.Query().Get("city")
-	if city == "" {
-		http.Error(w, "Missing 'city' parameter", http.StatusBadRequest)
+// server holds the dependencies shared by the HTTP handlers.
+type server struct {
+	cache     *cache.Cache
+	client    *api.Client
+	provider  api.Provider
+	units     units.Units // default units, overridable per request
+	vars      []string    // default optional variables, overridable per request
+	locations config.Locations
+}
+
+func (s *server) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	loc, err := s.requestLocation(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	u, err := s.requestUnits(r, loc)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
 		return
 	}
-	key := fmt.Sprintf("%s_current", city)
+	vars, err := s.requestVariables(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	key := fmt.Sprintf("%s_current_%s_%s", loc.key, u.Key(), api.VariablesKey(vars))
 
-	if data, ok := c.Get(key); ok {
+	if data, ok := s.cache.Get(key); ok {
 		writeJSON(w, data.(*model.WeatherResponse))
 		return
 	}
 
-	coords, err := api.GetCoordinates(city)
+	coords, err := loc.resolve(ctx, s.client, nil)
 	if err != nil {
-		http.Error(w, "Geocoding failed: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, "Geocoding failed", err)
 		return
 	}
-	res, err := api.GetWeather(coords.Latitude, coords.Longitude)
+	res, err := s.provider.Current(ctx, coords.Latitude, coords.Longitude, u, vars)
 	if err != nil {
-		http.Error(w, "Fetch failed: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, "Fetch failed", err)
 		return
 	}
-	c.Set(key, res)
+	res.Location = coords.Label()
+	s.cache.Set(key, res)
 	writeJSON(w, res)
 }
 
-func handleHourly(w http.ResponseWriter, r *http.Request, c *cache.Cache) {
-	city := r.URL.Query().Get("city")
+func (s *server) handleHourly(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	hoursStr := r.URL.Query().Get("hours")
-	if city == "" {
-		http.Error(w, "Missing 'city' parameter", http.StatusBadRequest)
+	daysStr := r.URL.Query().Get("days")
+	loc, err := s.requestLocation(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	u, err := s.requestUnits(r, loc)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
 		return
 	}
 
-	// Default to 6 hours if not provided
+	// Default to 6 hours if not provided; days alone returns every hour of those days
 	hours := 6
 	if hoursStr != "" {
 		if h, err := strconv.Atoi(hoursStr); err == nil {
 			hours = h
 		}
+	} else if daysStr != "" {
+		hours = 0
+	}
+
+	days := api.DaysForHours(hours)
+	if daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 && d <= api.MaxForecastDays {
+			days = d
+		}
 	}
 
-	key := fmt.Sprintf("%s_hourly", city)
-	if data, ok := c.Get(key); ok {
+	vars, err := s.requestVariables(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	tz, err := requestTimeZone(r, loc)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	key := fmt.Sprintf("%s_hourly_%d_%s_%s", loc.key, days, u.Key(), api.VariablesKey(vars))
+	if data, ok := s.cache.Get(key); ok {
 		forecast := data.(*model.HourlyForecast)
-		writeLimitedHourlyJSON(w, forecast, hours)
+		writeLimitedHourlyJSON(w, forecast, hours, tz)
+		return
+	}
+
+	coords, err := loc.resolve(ctx, s.client, nil)
+	if err != nil {
+		writeError(w, "Geocoding failed", err)
+		return
+	}
+
+	res, err := s.provider.Hourly(ctx, coords.Latitude, coords.Longitude, days, u, vars)
+	if err != nil {
+		writeError(w, "Fetch failed", err)
+		return
+	}
+	res.Location = coords.Label()
+
+	s.cache.Set(key, res)
+	writeLimitedHourlyJSON(w, res, hours, tz)
+}
+
+func (s *server) handleDaily(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	daysStr := r.URL.Query().Get("days")
+	loc, err := s.requestLocation(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	u, err := s.requestUnits(r, loc)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	// Default to 7 days if not provided
+	days := 7
+	if daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 && d <= api.MaxForecastDays {
+			days = d
+		}
+	}
+
+	key := fmt.Sprintf("%s_daily_%d_%s", loc.key, days, u.Key())
+	if data, ok := s.cache.Get(key); ok {
+		writeJSON(w, data.(*model.DailyForecast))
 		return
 	}
 
-	coords, err := api.GetCoordinates(city)
+	coords, err := loc.resolve(ctx, s.client, nil)
 	if err != nil {
-		http.Error(w, "Geocoding failed: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, "Geocoding failed", err)
 		return
 	}
 
-	res, err := api.GetHourly(coords.Latitude, coords.Longitude)
+	res, err := s.provider.Daily(ctx, coords.Latitude, coords.Longitude, days, u)
 	if err != nil {
-		http.Error(w, "Fetch failed: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, "Fetch failed", err)
 		return
 	}
+	res.Location = coords.Label()
+
+	s.cache.Set(key, res)
+	writeJSON(w, res)
+}
+
+// requestUnits applies the units, temperature_unit, wind_speed_unit,
+// precipitation_unit and pressure_unit query parameters to the server
+// defaults, or to the units of a saved location.
+func (s *server) requestUnits(r *http.Request, loc location) (units.Units, error) {
+	q := r.URL.Query()
+	u := s.units
+	if loc.saved != nil {
+		if system := loc.saved.Units.System; system != "" {
+			var err error
+			if u, err = units.System(system); err != nil {
+				return u, fmt.Errorf("saved location: %w", err)
+			}
+		}
+		u = u.With(loc.saved.Units.Units)
+	}
+	if system := q.Get("units"); system != "" {
+		var err error
+		if u, err = units.System(system); err != nil {
+			return u, err
+		}
+	}
+	u = u.With(units.Units{
+		Temperature:   q.Get("temperature_unit"),
+		WindSpeed:     q.Get("wind_speed_unit"),
+		Precipitation: q.Get("precipitation_unit"),
+		Pressure:      q.Get("pressure_unit"),
+	})
+	return u, u.Validate()
+}
+
+// requestLocation reads lat/lon, or city with the optional country and admin1
+// filters; city may name a saved location from the config. The server never
+// prompts, so ambiguous names resolve to the best-ranked match.
+func (s *server) requestLocation(r *http.Request) (location, error) {
+	q := r.URL.Query()
+	if latStr, lonStr := q.Get("lat"), q.Get("lon"); latStr != "" || lonStr != "" {
+		lat, latErr := strconv.ParseFloat(latStr, 64)
+		lon, lonErr := strconv.ParseFloat(lonStr, 64)
+		if latErr != nil || lonErr != nil {
+			return location{}, fmt.Errorf("'lat' and 'lon' must both be numbers")
+		}
+		if err := api.ValidateCoordinates(lat, lon); err != nil {
+			return location{}, err
+		}
+		return coordinatesLocation(lat, lon), nil
+	}
+
+	city, country, admin1 := q.Get("city"), q.Get("country"), q.Get("admin1")
+	if city == "" {
+		return location{}, fmt.Errorf("Missing 'city' parameter (or 'lat' and 'lon')")
+	}
+	if country == "" && admin1 == "" {
+		if name, saved, ok := s.locations.Lookup(city); ok {
+			loc, err := savedLocation(saved)
+			if err != nil {
+				return location{}, fmt.Errorf("invalid location %q: %w", name, err)
+			}
+			return loc, nil
+		}
+	}
+	return cityLocation(city, country, admin1), nil
+}
+
+// requestVariables returns the vars query parameter (comma-separated) or the server default.
+func (s *server) requestVariables(r *http.Request) ([]string, error) {
+	if v := r.URL.Query().Get("vars"); v != "" {
+		return api.NormalizeVariables([]string{v})
+	}
+	return s.vars, nil
+}
 
-	c.Set(key, res)
-	writeLimitedHourlyJSON(w, res, hours)
+// requestTimeZone returns the tz query parameter, else the time zone of a
+// saved location, else nil to keep the provider's UTC times.
+func requestTimeZone(r *http.Request, loc location) (*time.Location, error) {
+	name := r.URL.Query().Get("tz")
+	if name == "" && loc.saved != nil {
+		name = loc.saved.TimeZone
+	}
+	if name == "" {
+		return nil, nil
+	}
+	tz, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return tz, nil
 }
 
 func writeJSON(w http.ResponseWriter, data any) {
@@ -161,7 +352,11 @@ func writeJSON(w http.ResponseWriter, data any) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func writeLimitedHourlyJSON(w http.ResponseWriter, forecast *model.HourlyForecast, hours int) {
+// writeLimitedHourlyJSON writes the first hours of a forecast (all when
+// hours <= 0), with times shown in tz when it is set.
+func writeLimitedHourlyJSON(w http.ResponseWriter, cached *model.HourlyForecast, hours int, tz *time.Location) {
+	// Work on a copy so truncation never shrinks the cached forecast
+	forecast := *cached
 	limit := len(forecast.Hourly.Time)
 	if hours > 0 && hours < limit {
 		forecast.Hourly.Time = forecast.Hourly.Time[:hours]
@@ -171,9 +366,27 @@ func writeLimitedHourlyJSON(w http.ResponseWriter, forecast *model.HourlyForecas
 		forecast.Hourly.Pressure = forecast.Hourly.Pressure[:hours]
 		forecast.Hourly.Winddirection = forecast.Hourly.Winddirection[:hours]
 		forecast.Hourly.Weathercode = forecast.Hourly.Weathercode[:hours]
+		forecast.Series = make(map[string]model.Series, len(cached.Series))
+		for name, series := range cached.Series {
+			if len(series) > hours {
+				series = series[:hours]
+			}
+			forecast.Series[name] = series
+		}
+	}
+	if tz != nil {
+		times := make([]string, len(forecast.Hourly.Time))
+		for i, ts := range forecast.Hourly.Time {
+			times[i] = ts
+			if t, err := cli.ParseHour(ts); err == nil {
+				times[i] = t.In(tz).Format("2006-01-02T15:04")
+			}
+		}
+		forecast.Hourly.Time = times
+		forecast.Timezone = tz.String()
 	}
 
-	writeJSON(w, forecast)
+	writeJSON(w, &forecast)
 }
 
 // loggingMiddleware logs every HTTP request using zap.
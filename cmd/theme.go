@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var iconsFlag string
+
+// addIconsFlag registers --icons on commands that show weather conditions.
+func addIconsFlag(cmd *cobra.Command) {
+	sets := make([]string, len(api.IconSets))
+	for i, s := range api.IconSets {
+		sets[i] = string(s)
+	}
+	cmd.Flags().StringVar(&iconsFlag, "icons", "", "Condition icons: "+strings.Join(sets, "|")+" (default from config, else emoji)")
+	cmd.RegisterFlagCompletionFunc("icons", cobra.FixedCompletions(sets, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// newTheme builds the theme from --color and --emoji (the config's color
+// and emoji settings unless the flags are given; a theme named with --color
+// is used even when stdout is not a terminal) and settles the icon set
+// views use in cfg: --icons, else the config's icons. With emoji off the
+// emoji set shows no icons; the other sets are unaffected.
+func newTheme(cmd *cobra.Command, cfg *config.Config) ui.Theme {
+	color, emoji := cfg.Color, cfg.Emoji
+	forced := cmd.Flags().Changed("color")
+	if forced {
+		color = colorFlag
+	}
+	if cmd.Flags().Changed("emoji") {
+		emoji = emojiFlag
+	}
+	theme, err := ui.ResolveTheme(color, cfg.Themes, map[bool]string{true: "on", false: "off"}[emoji], forced)
+	if err != nil {
+		exitWithCode("invalid color theme", err, exitConfig)
+	}
+
+	name := cfg.Icons
+	if cmd.Flags().Changed("icons") {
+		name = iconsFlag
+	}
+	set, err := api.ParseIconSet(name)
+	if err != nil {
+		exitWithCode("invalid icon set", err, exitConfig)
+	}
+	if set == api.IconsEmoji && !theme.Emoji {
+		set = api.IconsNone
+	}
+	cfg.Icons = string(set)
+	return theme
+}
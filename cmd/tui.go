@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/cache"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/tui"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var tuiIntervalFlag time.Duration
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Interactive full-screen dashboard",
+		Long: `Show a full-screen dashboard with current conditions, a scrollable hourly
+forecast and a chart, next to a list of locations: the --city location (or
+the configured city), saved locations and places in the geocode cache.
+
+Keys:
+  Tab, Shift-Tab, 1-9   switch location
+  Up/Down, j/k          scroll the hourly forecast
+  PgUp/PgDn, Home/End   scroll by a page, back to now, to the end
+  u                     cycle units: configured, metric, imperial
+  t                     cycle color themes
+  c                     cycle the charted series
+  r                     refresh now
+  q, Esc, Ctrl-C        quit
+
+The shown location is refreshed every --interval in the background; data is
+read through the cache like every other command.`,
+		Example: `  goweather tui
+  goweather tui --city belgrade --interval 5m`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+			if tuiIntervalFlag < time.Minute {
+				exitWithCode("invalid interval", errors.New("--interval must be at least 1m"), exitConfig)
+			}
+			first := selectLocation(cmd, cfg)
+			theme := newTheme(cmd, cfg)
+			client := newClient(cfg)
+
+			err := tui.Run(ctx, tui.Options{
+				Locations: tuiLocations(cmd, cfg, client, first),
+				Provider:  newProvider(cfg, client),
+				Cache:     cache.NewCache(cfg.CacheDuration),
+				Units:     resolveUnits(cfg),
+				Vars:      resolveVariables(cfg),
+				Theme:     theme,
+				ThemeName: tuiThemeName(cmd, cfg, theme),
+				Interval:  tuiIntervalFlag,
+				Timeout:   bothTimeoutFlag,
+				Config:    cfg,
+			})
+			switch {
+			case errors.Is(err, tui.ErrNotTerminal):
+				exitWithCode("cannot start the dashboard", err, exitConfig)
+			case err != nil:
+				exitWithError("dashboard failed", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "Location shown first (default from config; or use --lat/--lon)")
+	cmd.Flags().DurationVar(&tuiIntervalFlag, "interval", 10*time.Minute, "How often to refresh the shown location (at least 1m)")
+	cmd.Flags().DurationVar(&bothTimeoutFlag, "timeout", 30*time.Second, "Deadline of each refresh (0 for none)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Initial color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
+	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
+	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+
+	rootCmd.AddCommand(cmd)
+}
+
+// tuiLocations lists first, then the saved locations and the places in the
+// geocode cache, without duplicates.
+func tuiLocations(cmd *cobra.Command, cfg *config.Config, client *api.Client, first location) []tui.Location {
+	var out []tui.Location
+	seen := make(map[string]bool)
+	add := func(name string, loc location) {
+		if seen[loc.key] {
+			return
+		}
+		seen[loc.key] = true
+		out = append(out, tui.Location{
+			Name: name,
+			Key:  loc.key,
+			Resolve: func(ctx context.Context) (*api.Coordinates, error) {
+				return loc.resolve(ctx, client, nil)
+			},
+		})
+	}
+
+	name := cityFlag
+	switch {
+	case cmd.Flags().Changed("lat"):
+		name = first.coords.Label()
+	case name == "":
+		name = cfg.City
+	}
+	add(name, first)
+	for _, name := range cfg.Locations.Names() {
+		loc, err := lookupLocation(name, cfg)
+		if err != nil {
+			log.Logger.Warnw("Skipping invalid saved location", "name", name, "error", err)
+			continue
+		}
+		add(name, loc)
+	}
+	if entries, err := api.NewGeocodeCache(cfg.Geocode.CacheTTL).Entries(); err == nil {
+		for _, e := range entries {
+			// Keys with "|" were geocoded with filters
+			if !strings.Contains(e.Key, "|") && !e.Expired(cfg.Geocode.CacheTTL) {
+				add(e.Name, cityLocation(e.Key, "", ""))
+			}
+		}
+	}
+	return out
+}
+
+// tuiThemeName names the initial theme in the header; auto is shown as the
+// built-in theme it selected.
+func tuiThemeName(cmd *cobra.Command, cfg *config.Config, theme ui.Theme) string {
+	name := cfg.Color
+	if cmd.Flags().Changed("color") {
+		name = colorFlag
+	}
+	if !strings.EqualFold(name, "auto") {
+		return name
+	}
+	if theme.Reset == "" {
+		return "none"
+	}
+	return "dark"
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"goweather/internal/config"
+	"goweather/internal/units"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	unitsFlag        string
+	tempUnitFlag     string
+	windUnitFlag     string
+	precipUnitFlag   string
+	pressureUnitFlag string
+)
+
+// addUnitFlags registers the unit selection flags on a weather command.
+func addUnitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&unitsFlag, "units", "", "Unit system: metric|imperial (default from config)")
+	cmd.Flags().StringVar(&tempUnitFlag, "temp-unit", "", "Temperature unit: celsius|fahrenheit")
+	cmd.Flags().StringVar(&windUnitFlag, "wind-unit", "", "Wind speed unit: kmh|ms|mph|kn")
+	cmd.Flags().StringVar(&precipUnitFlag, "precip-unit", "", "Precipitation unit: mm|inch")
+	cmd.Flags().StringVar(&pressureUnitFlag, "pressure-unit", "", "Pressure unit: hpa|inhg|mmhg")
+}
+
+// resolveUnits combines the config's unit section with the unit flags.
+// Flags win over config; per-quantity settings win over the unit system.
+func resolveUnits(cfg *config.Config) units.Units {
+	system := cfg.Units.System
+	override := cfg.Units.Units
+	if unitsFlag != "" {
+		// An explicit system on the command line replaces the configured one entirely
+		system, override = unitsFlag, units.Units{}
+	}
+
+	u, err := units.System(system)
+	if err != nil {
+		exitWithCode("invalid units", err, exitConfig)
+	}
+	u = u.With(override).With(units.Units{
+		Temperature:   tempUnitFlag,
+		WindSpeed:     windUnitFlag,
+		Precipitation: precipUnitFlag,
+		Pressure:      pressureUnitFlag,
+	})
+	if err := u.Validate(); err != nil {
+		exitWithCode("invalid units", err, exitConfig)
+	}
+	return u
+}
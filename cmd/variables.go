@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var varsFlag []string
+
+// addVarsFlag registers --vars on commands that show current or hourly data.
+func addVarsFlag(cmd *cobra.Command) {
+	names := make([]string, len(api.Variables))
+	for i, v := range api.Variables {
+		names[i] = v.Name
+	}
+	cmd.Flags().StringSliceVar(&varsFlag, "vars", nil,
+		"Extra variables to show, e.g. apparent_temperature,uv_index (known: "+strings.Join(names, ", ")+"; any Open-Meteo variable works)")
+}
+
+// resolveVariables returns the --vars selection, falling back to config.
+func resolveVariables(cfg *config.Config) []string {
+	names := cfg.Variables
+	if len(varsFlag) > 0 {
+		names = varsFlag
+	}
+	vars, err := api.NormalizeVariables(names)
+	if err != nil {
+		exitWithCode("invalid variables", err, exitConfig)
+	}
+	return vars
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"goweather/internal/cache"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var watchIntervalFlag time.Duration
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Keep current conditions and the hourly forecast on screen, refreshing periodically",
+		Long: `Show current conditions and the hourly forecast and refresh them every
+--interval until Ctrl-C. The first view may come from the cache; every
+refresh calls the weather service and updates the cache.
+
+On a terminal the view is redrawn in place on the alternate screen, which is
+restored on exit. When stdout is not a terminal every refresh is appended.`,
+		Example: `  goweather watch --city belgrade
+  goweather watch --city home --interval 5m --hours 12`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := cmd.Context()
+			cfg, _ := config.Load()
+			log.Init(verboseFlag)
+			defer log.Sync()
+			loc := selectLocation(cmd, cfg)
+			if watchIntervalFlag < time.Minute {
+				exitWithCode("invalid interval", fmt.Errorf("--interval must be at least 1m, got %s", watchIntervalFlag), exitConfig)
+			}
+
+			theme := newTheme(cmd, cfg)
+			client := newClient(cfg)
+			provider := newProvider(cfg, client)
+			u := resolveUnits(cfg)
+			vars := resolveVariables(cfg)
+			c := cache.NewCache(cfg.CacheDuration)
+			coords, err := resolveLocation(ctx, client, loc, theme)
+			if err != nil {
+				exitWithError("geocoding failed", err)
+			}
+
+			f := &cli.BothFetcher{Provider: provider, Units: u, Vars: vars, Coords: coords, Cache: c, Key: loc.key, Hours: hoursFlag}
+			err = cli.Watch(ctx, os.Stdout, f, cli.WatchOptions{
+				Interval: watchIntervalFlag,
+				Timeout:  bothTimeoutFlag,
+				Renderer: cli.NewTableRenderer(theme, tableOptions(cmd, cfg)),
+				Theme:    theme,
+				Screen:   ui.IsTerminal(os.Stdout),
+			}, cfg)
+			if err != nil {
+				exitWithError("watch failed", err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&cityFlag, "city", "c", "", "City name or saved location (default from config; or use --lat/--lon)")
+	cmd.Flags().DurationVar(&watchIntervalFlag, "interval", 10*time.Minute, "How often to refresh (at least 1m)")
+	cmd.Flags().IntVar(&hoursFlag, "hours", 6, "Number of hours to display")
+	cmd.Flags().DurationVar(&bothTimeoutFlag, "timeout", 30*time.Second, "Deadline shared by the current and hourly fetches (0 for none)")
+	cmd.Flags().StringVar(&colorFlag, "color", "auto", "Color theme: auto|dark|light|none, a theme from the config or a .yaml theme file")
+	cmd.Flags().BoolVar(&emojiFlag, "emoji", true, "Enable emoji output")
+	addIconsFlag(cmd)
+	cmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Verbose logging")
+	addLocationFlags(cmd)
+	addUnitFlags(cmd)
+	addVarsFlag(cmd)
+	addSparklineFlag(cmd)
+	addScaleFlags(cmd)
+
+	rootCmd.AddCommand(cmd)
+}
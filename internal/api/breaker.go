@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrCircuitOpen is returned without contacting upstream while a host's
+// circuit breaker is open. It matches ErrUpstreamUnavailable.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker open: %w", ErrUpstreamUnavailable)
+
+// BreakerPolicy controls when upstream hosts are temporarily skipped.
+// A FailureThreshold of zero disables the breaker.
+type BreakerPolicy struct {
+	FailureThreshold int           // consecutive failed requests before opening
+	Cooldown         time.Duration // how long to fail fast before probing again
+}
+
+// DefaultBreakerPolicy opens after five consecutive failures for 30 seconds.
+var DefaultBreakerPolicy = BreakerPolicy{FailureThreshold: 5, Cooldown: 30 * time.Second}
+
+type breakerState int
+
+// Values match the goweather_circuit_breaker_state gauge.
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks consecutive failures for a single upstream host.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	host     string
+	policy   BreakerPolicy
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(host string, policy BreakerPolicy) *circuitBreaker {
+	b := &circuitBreaker{host: host, policy: policy}
+	b.publish()
+	return b
+}
+
+// allow reports whether a request may be sent. After the cool-down a single
+// probe request is let through in the half-open state.
+func (b *circuitBreaker) allow() error {
+	if b.policy.FailureThreshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.setState(breakerHalfOpen)
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// success closes the breaker and resets the failure count.
+func (b *circuitBreaker) success() {
+	if b.policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	b.setState(breakerClosed)
+}
+
+// failure records an upstream failure and opens the breaker when the
+// threshold is reached or a half-open probe fails.
+func (b *circuitBreaker) failure() {
+	if b.policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.openedAt = time.Now()
+		b.setState(breakerOpen)
+	}
+}
+
+// release gives up a half-open probe that ended without an upstream verdict
+// (e.g. the caller cancelled).
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) setState(s breakerState) {
+	b.state = s
+	b.publish()
+}
+
+func (b *circuitBreaker) publish() {
+	circuitBreakerState.WithLabelValues(b.host).Set(float64(b.state))
+}
+
+var circuitBreakerState = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "goweather_circuit_breaker_state",
+		Help: "Upstream circuit breaker state per host (0=closed, 1=open, 2=half-open)",
+	},
+	[]string{"host"},
+)
+
+func init() {
+	// Register metrics once when this package is loaded
+	prometheus.MustRegister(circuitBreakerState)
+}
@@ -1,84 +1,246 @@
 package api
 
 import (
-	"encoding/json"
-	"fmt"
-	"math"
+	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"goweather/internal/log"
-	"goweather/internal/model"
+
+	"go.uber.org/zap"
 )
 
-// doWithRetry performs HTTP GET with exponential backoff.
-func doWithRetry(url string, maxRetries int) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-	for i := 0; i < maxRetries; i++ {
-		resp, err = http.Get(url)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			return resp, nil
-		}
-		delay := time.Duration(math.Pow(2, float64(i))) * time.Second
-		log.Logger.Warnw("Request failed, retrying",
-			"url", url, "attempt", i+1, "wait", delay)
-		time.Sleep(delay)
-	}
-	return resp, fmt.Errorf("all retries failed: %v", err)
+// Default upstream endpoints and client settings.
+const (
+	DefaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1"
+	DefaultUserAgent    = "goweather/1.0 github.com/predrag86/goweather"
+	DefaultTimeout      = 10 * time.Second
+)
+
+// Client performs HTTP requests against the forecast and geocoding APIs.
+// Build it once with NewClient and share it between providers and handlers.
+type Client struct {
+	forecastURL  string
+	geocodingURL string
+	httpClient   *http.Client
+	userAgent    string
+	retry        RetryPolicy
+	breaker      BreakerPolicy
+	logger       *zap.SugaredLogger
+	geocache     *GeocodeCache
+
+	breakersMu sync.Mutex
+	breakers   map[string]*circuitBreaker // keyed by upstream host
 }
 
-// GetWeather fetches current weather data with retry/backoff.
-func GetWeather(lat, lon float64) (*model.WeatherResponse, error) {
-	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&current=temperature_2m,relative_humidity_2m,windspeed_10m,winddirection_10m,weathercode,surface_pressure",
-		lat, lon)
+// Option configures a Client.
+type Option func(*Client)
 
-	log.Logger.Infow("Requesting current weather", "lat", lat, "lon", lon)
+// WithForecastURL overrides the forecast API base URL of the selected provider
+// (e.g. a self-hosted Open-Meteo instance).
+func WithForecastURL(url string) Option {
+	return func(c *Client) { c.forecastURL = url }
+}
 
-	resp, err := doWithRetry(url, 3)
-	if err != nil {
-		log.Logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
-		return nil, err
+// WithGeocodingURL overrides the Open-Meteo geocoding API base URL.
+func WithGeocodingURL(url string) Option {
+	return func(c *Client) { c.geocodingURL = url }
+}
+
+// WithHTTPClient sets the HTTP client used for every upstream request.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithUserAgent sets the User-Agent header sent upstream.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRetryPolicy sets the retry/backoff policy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithBreakerPolicy sets the circuit breaker policy applied per upstream host.
+func WithBreakerPolicy(p BreakerPolicy) Option {
+	return func(c *Client) { c.breaker = p }
+}
+
+// WithLogger sets the logger used by the client and its providers.
+func WithLogger(l *zap.SugaredLogger) Option {
+	return func(c *Client) { c.logger = l }
+}
+
+// WithGeocodeCache sets where geocoding results are remembered.
+func WithGeocodeCache(gc *GeocodeCache) Option {
+	return func(c *Client) { c.geocache = gc }
+}
+
+// NewClient creates a Client with defaults overridden by opts.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		geocodingURL: DefaultGeocodingURL,
+		httpClient:   &http.Client{Timeout: DefaultTimeout},
+		userAgent:    DefaultUserAgent,
+		retry:        DefaultRetryPolicy,
+		breaker:      DefaultBreakerPolicy,
+		logger:       log.Logger,
+		geocache:     NewGeocodeCache(0),
+		breakers:     make(map[string]*circuitBreaker),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
-	defer resp.Body.Close()
+	if c.logger == nil {
+		c.logger = zap.NewNop().Sugar()
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c
+}
 
-	var w model.WeatherResponse
-	if err := json.NewDecoder(resp.Body).Decode(&w); err != nil {
-		log.Logger.Errorw("JSON decode failed", "error", err)
-		return nil, fmt.Errorf("decode error: %v", err)
+// forecastBase returns the configured forecast URL or the provider's default.
+func (c *Client) forecastBase(def string) string {
+	if c.forecastURL != "" {
+		return c.forecastURL
 	}
+	return def
+}
 
-	log.Logger.Infow("Weather data retrieved",
-		"temperature", w.Current.Temperature,
-		"wind", w.Current.Windspeed,
-		"pressure", w.Current.Pressure,
-		"humidity", w.Current.Humidity)
+// breakerFor returns the circuit breaker guarding the host of rawURL.
+func (c *Client) breakerFor(rawURL string) *circuitBreaker {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Host
+	}
 
-	return &w, nil
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+	b, ok := c.breakers[host]
+	if !ok {
+		b = newCircuitBreaker(host, c.breaker)
+		c.breakers[host] = b
+	}
+	return b
 }
 
-// GetHourly fetches hourly forecast data with retry/backoff.
-func GetHourly(lat, lon float64) (*model.HourlyForecast, error) {
-	url := fmt.Sprintf(
-		"https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&hourly=temperature_2m,relative_humidity_2m,windspeed_10m,winddirection_10m,weathercode,surface_pressure&forecast_days=1",
-		lat, lon)
-
-	log.Logger.Infow("Requesting hourly forecast", "lat", lat, "lon", lon)
+// get performs HTTP GET following the retry policy. Permanent 4xx responses
+// are not retried, Retry-After is honored and failed response bodies are
+// always closed. Cancelling ctx aborts both the in-flight request and any
+// pending backoff sleep. Repeated failures open the host's circuit breaker:
+// 2xx responses count as healthy and network errors, 5xx, 408 and 429 as
+// failures. Other 4xx responses leave the breaker alone: they blame the
+// request, not the host, so bad requests from one caller cannot cut the
+// host off for everyone.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	breaker := c.breakerFor(url)
+	if err := breaker.allow(); err != nil {
+		c.logger.Warnw("Circuit breaker open, skipping request", "url", url)
+		return nil, err
+	}
 
-	resp, err := doWithRetry(url, 3)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		log.Logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
+		breaker.release()
 		return nil, err
 	}
-	defer resp.Body.Close()
+	req.Header.Set("User-Agent", c.userAgent)
+
+	var upErr *UpstreamError
+	for i := 0; i < c.retry.MaxAttempts; i++ {
+		resp, err := c.httpClient.Do(req)
+		if err == nil && healthyStatus(resp.StatusCode) {
+			breaker.success()
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			if err == nil {
+				drainAndClose(resp)
+			}
+			breaker.release()
+			return nil, ctx.Err()
+		}
+
+		upErr = &UpstreamError{URL: url, Err: err}
+		delay := c.retry.backoff(i)
+		if err == nil {
+			drainAndClose(resp)
+			upErr.StatusCode = resp.StatusCode
+			if !retryableStatus(resp.StatusCode) {
+				// Bad parameters or unknown resources won't improve on retry
+				breaker.release()
+				c.logger.Errorw("Request failed permanently", "url", url, "status", resp.StatusCode)
+				return nil, upErr
+			}
+			if wait, ok := retryAfter(resp); ok {
+				upErr.RetryAfter = wait
+				if c.retry.MaxDelay > 0 && wait > c.retry.MaxDelay {
+					c.logger.Warnw("Retry-After exceeds max delay, giving up",
+						"url", url, "retry_after", wait, "max_delay", c.retry.MaxDelay)
+					break
+				}
+				delay = wait
+			}
+		}
+
+		if i == c.retry.MaxAttempts-1 {
+			break
+		}
+		c.logger.Warnw("Request failed, retrying",
+			"url", url, "attempt", i+1, "wait", delay, "error", upErr)
+		if err := sleepContext(ctx, delay); err != nil {
+			breaker.release()
+			return nil, err
+		}
+	}
+
+	breaker.failure()
+	return nil, upErr
+}
+
+// healthyStatus reports whether a response status counts as a success for
+// the circuit breaker: any 2xx. Permanent 4xx are neither success nor failure.
+func healthyStatus(code int) bool {
+	return code >= 200 && code < 300
+}
+
+// drainAndClose discards the rest of a response body so the connection can be reused.
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
 
-	var h model.HourlyForecast
-	if err := json.NewDecoder(resp.Body).Decode(&h); err != nil {
-		log.Logger.Errorw("JSON decode failed", "error", err)
-		return nil, fmt.Errorf("decode error: %v", err)
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
-	log.Logger.Infow("Hourly data retrieved", "records", len(h.Hourly.Time))
-	return &h, nil
+// MaxForecastDays is the longest forecast range Open-Meteo provides.
+const MaxForecastDays = 16
+
+// DaysForHours returns how many forecast days are needed to cover the given hours.
+func DaysForHours(hours int) int {
+	return clampDays((hours + 23) / 24)
+}
+
+func clampDays(days int) int {
+	if days < 1 {
+		return 1
+	}
+	if days > MaxForecastDays {
+		return MaxForecastDays
+	}
+	return days
 }
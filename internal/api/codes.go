@@ -1,44 +1,144 @@
 package api
 
-// WeatherDescription converts Open-Meteo weather codes to text/emoji.
-func WeatherDescription(code int) string {
+// Condition is a provider-neutral weather condition. Every provider maps its
+// own condition codes onto it so renderers only deal with one vocabulary.
+type Condition int
+
+const (
+	ConditionUnknown Condition = iota
+	ConditionClear
+	ConditionPartlyCloudy
+	ConditionOvercast
+	ConditionFog
+	ConditionDrizzle
+	ConditionFreezingDrizzle
+	ConditionRain
+	ConditionFreezingRain
+	ConditionSnow
+	ConditionSnowGrains
+	ConditionRainShowers
+	ConditionSnowShowers
+	ConditionThunderstorm
+	ConditionThunderstormHail
+)
+
+// ConditionFromWMO maps an Open-Meteo (WMO 4677) weather code to a Condition.
+func ConditionFromWMO(code int) Condition {
 	switch code {
 	case 0:
-		return "☀️ Clear sky"
+		return ConditionClear
 	case 1, 2:
-		return "🌤️ Partly cloudy"
+		return ConditionPartlyCloudy
 	case 3:
-		return "☁️ Overcast"
+		return ConditionOvercast
 
 	case 45, 48:
-		return "🌫️ Fog"
+		return ConditionFog
 
 	case 51, 53, 55:
-		return "🌦️ Drizzle"
+		return ConditionDrizzle
 	case 56, 57:
-		return "🌧️ Freezing drizzle"
+		return ConditionFreezingDrizzle
 
 	case 61, 63, 65:
-		return "🌧️ Rain"
+		return ConditionRain
 	case 66, 67:
-		return "🌧️ Freezing rain"
+		return ConditionFreezingRain
 
 	case 71, 73, 75:
-		return "🌨️ Snow"
+		return ConditionSnow
 	case 77:
-		return "❄️ Snow grains"
+		return ConditionSnowGrains
 
 	case 80, 81, 82:
-		return "🌧️ Rain showers"
+		return ConditionRainShowers
 	case 85, 86:
-		return "🌨️ Snow showers"
+		return ConditionSnowShowers
 
 	case 95:
-		return "⛈️ Thunderstorm"
+		return ConditionThunderstorm
 	case 96, 99:
-		return "🌩️ Thunderstorm with hail"
+		return ConditionThunderstormHail
+
+	default:
+		return ConditionUnknown
+	}
+}
 
+// WMOCode returns a representative WMO weather code for the condition.
+// Providers with their own code sets use it to fill the model's Weathercode fields.
+func (c Condition) WMOCode() int {
+	switch c {
+	case ConditionClear:
+		return 0
+	case ConditionPartlyCloudy:
+		return 2
+	case ConditionOvercast:
+		return 3
+	case ConditionFog:
+		return 45
+	case ConditionDrizzle:
+		return 53
+	case ConditionFreezingDrizzle:
+		return 56
+	case ConditionRain:
+		return 63
+	case ConditionFreezingRain:
+		return 66
+	case ConditionSnow:
+		return 73
+	case ConditionSnowGrains:
+		return 77
+	case ConditionRainShowers:
+		return 80
+	case ConditionSnowShowers:
+		return 85
+	case ConditionThunderstorm:
+		return 95
+	case ConditionThunderstormHail:
+		return 96
 	default:
-		return "🌈 Unknown"
+		return -1
 	}
 }
+
+// String returns a plain-text description of the condition.
+func (c Condition) String() string {
+	switch c {
+	case ConditionClear:
+		return "Clear sky"
+	case ConditionPartlyCloudy:
+		return "Partly cloudy"
+	case ConditionOvercast:
+		return "Overcast"
+	case ConditionFog:
+		return "Fog"
+	case ConditionDrizzle:
+		return "Drizzle"
+	case ConditionFreezingDrizzle:
+		return "Freezing drizzle"
+	case ConditionRain:
+		return "Rain"
+	case ConditionFreezingRain:
+		return "Freezing rain"
+	case ConditionSnow:
+		return "Snow"
+	case ConditionSnowGrains:
+		return "Snow grains"
+	case ConditionRainShowers:
+		return "Rain showers"
+	case ConditionSnowShowers:
+		return "Snow showers"
+	case ConditionThunderstorm:
+		return "Thunderstorm"
+	case ConditionThunderstormHail:
+		return "Thunderstorm with hail"
+	default:
+		return "Unknown"
+	}
+}
+
+// WeatherIcon returns the emoji for a WMO weather code.
+func WeatherIcon(code int) string {
+	return ConditionFromWMO(code).Icon(IconsEmoji)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors returned (wrapped) by the api package. Match them with errors.Is.
+var (
+	ErrLocationNotFound    = errors.New("location not found")
+	ErrUpstreamUnavailable = errors.New("upstream weather service unavailable")
+	ErrRateLimited         = errors.New("rate limited by upstream weather service")
+	ErrUpstreamRejected    = errors.New("upstream weather service rejected the request")
+	ErrDecode              = errors.New("could not decode upstream response")
+)
+
+// UpstreamError describes an upstream request that failed after retries.
+// It matches ErrRateLimited for 429 responses, ErrUpstreamRejected for other
+// client errors (4xx except 408), which a retry won't fix, and
+// ErrUpstreamUnavailable otherwise.
+type UpstreamError struct {
+	URL        string
+	StatusCode int           // 0 when no HTTP response was received
+	RetryAfter time.Duration // Retry-After of a 429 response, if any
+	Err        error         // transport error, if any
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("upstream returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("upstream request failed: %v", e.Err)
+}
+
+func (e *UpstreamError) Unwrap() error { return e.Err }
+
+// Is lets errors.Is match the sentinel that describes this failure.
+func (e *UpstreamError) Is(target error) bool {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusRequestTimeout:
+		return target == ErrUpstreamRejected
+	}
+	return target == ErrUpstreamUnavailable
+}
+
+// decodeError wraps a JSON decoding failure so it matches ErrDecode.
+func decodeError(err error) error {
+	return fmt.Errorf("%w: %v", ErrDecode, err)
+}
@@ -0,0 +1,31 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package api
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// lockFile takes an exclusive lock by creating path, waiting while another
+// process holds it. Locks older than staleLock are assumed abandoned.
+func lockFile(path string) (func(), error) {
+	const staleLock = 10 * time.Second
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// cache file for geocoding lookups
+const cacheFile = "geocode_cache.json"
+
+// GeocodeCache remembers geocoding results in a JSON file shared by every
+// goweather process. Writes take a file lock and replace the file atomically.
+type GeocodeCache struct {
+	path string
+	ttl  time.Duration // zero keeps entries forever
+}
+
+// GeocodeCacheEntry is one remembered lookup.
+type GeocodeCacheEntry struct {
+	Key string `json:"-"`
+	Coordinates
+	CachedAt time.Time `json:"cached_at,omitempty"` // zero for entries written by older versions
+}
+
+// Expired reports whether the entry is older than ttl. Entries without a
+// timestamp count as expired once a TTL is set.
+func (e GeocodeCacheEntry) Expired(ttl time.Duration) bool {
+	return ttl > 0 && (e.CachedAt.IsZero() || time.Since(e.CachedAt) > ttl)
+}
+
+// NewGeocodeCache opens the geocode cache in the user cache directory.
+func NewGeocodeCache(ttl time.Duration) *GeocodeCache {
+	dir, _ := os.UserCacheDir()
+	return &GeocodeCache{path: filepath.Join(dir, "goweather", cacheFile), ttl: ttl}
+}
+
+// Path returns the cache file location.
+func (gc *GeocodeCache) Path() string { return gc.path }
+
+// TTL returns how long entries stay valid; zero means forever.
+func (gc *GeocodeCache) TTL() time.Duration { return gc.ttl }
+
+// Get returns an unexpired entry for a key built with GeocodeCacheKey.
+func (gc *GeocodeCache) Get(key string) (Coordinates, bool) {
+	entries, err := gc.load()
+	if err != nil {
+		return Coordinates{}, false
+	}
+	e, ok := entries[key]
+	if !ok || e.Expired(gc.ttl) {
+		return Coordinates{}, false
+	}
+	return e.Coordinates, true
+}
+
+// Put stores a result under key.
+func (gc *GeocodeCache) Put(key string, c Coordinates) error {
+	return gc.update(func(entries map[string]GeocodeCacheEntry) int {
+		entries[key] = GeocodeCacheEntry{Key: key, Coordinates: c, CachedAt: time.Now()}
+		return 1
+	})
+}
+
+// Entries lists every entry, expired or not, sorted by key.
+func (gc *GeocodeCache) Entries() ([]GeocodeCacheEntry, error) {
+	entries, err := gc.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]GeocodeCacheEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Delete removes the given keys and returns how many existed.
+func (gc *GeocodeCache) Delete(keys ...string) (int, error) {
+	var n int
+	err := gc.update(func(entries map[string]GeocodeCacheEntry) int {
+		for _, k := range keys {
+			if _, ok := entries[k]; ok {
+				delete(entries, k)
+				n++
+			}
+		}
+		return n
+	})
+	return n, err
+}
+
+// Purge removes entries that are expired under maxAge, or every entry when
+// maxAge is zero, and returns how many were removed.
+func (gc *GeocodeCache) Purge(maxAge time.Duration) (int, error) {
+	var n int
+	err := gc.update(func(entries map[string]GeocodeCacheEntry) int {
+		for k, e := range entries {
+			if maxAge == 0 || e.Expired(maxAge) {
+				delete(entries, k)
+				n++
+			}
+		}
+		return n
+	})
+	return n, err
+}
+
+// load reads the cache file. Keys from older versions are normalized on the
+// fly; for duplicates the newest entry wins.
+func (gc *GeocodeCache) load() (map[string]GeocodeCacheEntry, error) {
+	entries := make(map[string]GeocodeCacheEntry)
+	data, err := os.ReadFile(gc.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]GeocodeCacheEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		// A corrupt cache is only a cache: start over rather than fail lookups
+		return entries, nil
+	}
+	for k, e := range raw {
+		e.Key = normalizeCacheKey(k)
+		if old, ok := entries[e.Key]; !ok || e.CachedAt.After(old.CachedAt) {
+			entries[e.Key] = e
+		}
+	}
+	return entries, nil
+}
+
+// update applies fn under an exclusive file lock and writes the result via a
+// temporary file and rename, so readers never see a partial file and
+// concurrent writers never lose each other's entries. fn returns the number
+// of changes; nothing is written when it is zero.
+func (gc *GeocodeCache) update(fn func(map[string]GeocodeCacheEntry) int) error {
+	if err := os.MkdirAll(filepath.Dir(gc.path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(gc.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := gc.load()
+	if err != nil {
+		return err
+	}
+	if fn(entries) == 0 {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(gc.path), cacheFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := json.NewEncoder(tmp)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entries); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), gc.path)
+}
+
+// GeocodeCacheKey is the cache key for a lookup: the case-folded name with
+// whitespace collapsed, plus the filters so a filtered choice never shadows
+// the plain name.
+func GeocodeCacheKey(city string, opts GeocodeOptions) string {
+	key := city
+	if opts.Country != "" || opts.Admin1 != "" {
+		key = strings.Join([]string{city, opts.Country, opts.Admin1}, "|")
+	}
+	return normalizeCacheKey(key)
+}
+
+// normalizeCacheKey case-folds each "|"-separated part and collapses whitespace.
+func normalizeCacheKey(key string) string {
+	parts := strings.Split(key, "|")
+	for i, p := range parts {
+		parts[i] = strings.Join(strings.Fields(foldCase(p)), " ")
+	}
+	return strings.Join(parts, "|")
+}
+
+// foldCase applies simple Unicode case folding (upper- then lower-casing each
+// rune), so "BELGRADE" and "belgrade" compare equal, as do "ΣΟΦΙΑ", "σοφια"
+// and a final sigma "ς".
+func foldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, s)
+}
@@ -1,33 +1,64 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"os"
-	"path/filepath"
+	"net/url"
+	"strings"
 
-	"goweather/internal/log"
 	"goweather/internal/model"
 )
 
 // Coordinates holds latitude/longitude pair for a city.
 type Coordinates struct {
-	Name      string  `json:"name"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Country   string  `json:"country"`
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code,omitempty"`
+	Admin1      string  `json:"admin1,omitempty"`
+	Population  int     `json:"population,omitempty"`
+	Timezone    string  `json:"timezone,omitempty"`
 }
 
-// cache file for geocoding lookups
-const cacheFile = "geocode_cache.json"
+// Label is a human-readable "name, region, country" description.
+func (c Coordinates) Label() string {
+	parts := []string{c.Name}
+	if c.Admin1 != "" && c.Admin1 != c.Name {
+		parts = append(parts, c.Admin1)
+	}
+	if c.Country != "" {
+		parts = append(parts, c.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DefaultGeocodeCount is how many candidates are requested when resolving a name.
+const DefaultGeocodeCount = 10
+
+// GeocodeOptions narrows down a geocoding lookup.
+type GeocodeOptions struct {
+	Country string // ISO 3166-1 alpha-2 code or country name
+	Admin1  string // first-level region, e.g. a state or province
+	// Choose picks one of several places the name could mean and returns its
+	// index. It is only asked when the name is ambiguous (see competing);
+	// when nil the best-ranked candidate is used.
+	Choose func(candidates []Coordinates) (int, error)
+}
+
+// GetCoordinates returns the best-ranked match for a city.
+func (c *Client) GetCoordinates(ctx context.Context, city string) (*Coordinates, error) {
+	return c.Geocode(ctx, city, GeocodeOptions{})
+}
 
-// GetCoordinates returns coordinates for a city, using Open-Meteo’s free geocoding API.
-// Results are cached to reduce network calls.
-func GetCoordinates(city string) (*Coordinates, error) {
-	cache, _ := loadCache()
-	if val, ok := cache[city]; ok {
-		log.Logger.Infow("Geocoding cache hit",
+// Geocode resolves a city via SearchLocations, applying the
+// country/admin1 filters and asking opts.Choose when the name is ambiguous.
+// The result is remembered in the geocode cache under the name and filters.
+func (c *Client) Geocode(ctx context.Context, city string, opts GeocodeOptions) (*Coordinates, error) {
+	key := GeocodeCacheKey(city, opts)
+	if val, ok := c.geocache.Get(key); ok {
+		c.logger.Infow("Geocoding cache hit",
 			"city", city,
 			"lat", val.Latitude,
 			"lon", val.Longitude,
@@ -35,46 +66,35 @@ func GetCoordinates(city string) (*Coordinates, error) {
 		return &val, nil
 	}
 
-	log.Logger.Infow("Calling Open-Meteo geocoding API", "city", city)
-	url := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1", city)
-
-	resp, err := http.Get(url)
+	candidates, err := c.SearchLocations(ctx, city, opts, DefaultGeocodeCount)
 	if err != nil {
-		log.Logger.Errorw("HTTP request failed", "url", url, "error", err)
-		return nil, fmt.Errorf("geocode request failed: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		log.Logger.Errorw("Geocoding API returned non-200 status",
-			"status", resp.Status, "url", url)
-		return nil, fmt.Errorf("geocode failed: %s", resp.Status)
-	}
-
-	var geo model.GeocodeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&geo); err != nil {
-		log.Logger.Errorw("Failed to decode geocoding response", "error", err)
-		return nil, fmt.Errorf("decode failed: %v", err)
+	if len(candidates) == 0 {
+		c.logger.Warnw("No geocoding results found", "city", city, "country", opts.Country, "admin1", opts.Admin1)
+		return nil, fmt.Errorf("%w: no coordinates found for %q", ErrLocationNotFound, city)
 	}
 
-	if len(geo.Results) == 0 {
-		log.Logger.Warnw("No geocoding results found", "city", city)
-		return nil, fmt.Errorf("no coordinates found for %s", city)
+	coord := candidates[0]
+	if choices := competing(candidates); choices != nil && opts.Choose != nil {
+		idx, err := opts.Choose(choices)
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 || idx >= len(choices) {
+			return nil, fmt.Errorf("invalid location choice %d", idx+1)
+		}
+		coord = choices[idx]
 	}
 
-	res := geo.Results[0]
-	coord := Coordinates{
-		Name:      res.Name,
-		Latitude:  res.Latitude,
-		Longitude: res.Longitude,
-		Country:   res.Country,
+	if err := c.geocache.Put(key, coord); err != nil {
+		c.logger.Warnw("Failed to update geocode cache", "path", c.geocache.Path(), "error", err)
 	}
 
-	cache[city] = coord
-	_ = saveCache(cache)
-
-	log.Logger.Infow("Geocoding success",
+	c.logger.Infow("Geocoding success",
 		"city", coord.Name,
+		"country", coord.Country,
+		"admin1", coord.Admin1,
 		"lat", coord.Latitude,
 		"lon", coord.Longitude,
 	)
@@ -82,38 +102,107 @@ func GetCoordinates(city string) (*Coordinates, error) {
 	return &coord, nil
 }
 
-// local cache helpers
-func cachePath() string {
-	dir, _ := os.UserCacheDir()
-	path := filepath.Join(dir, "goweather", cacheFile)
-	_ = os.MkdirAll(filepath.Dir(path), 0755)
-	return path
+// clearLead is how many times more populous the best-ranked place must be
+// than every other place of the same name to be taken without asking.
+const clearLead = 10
+
+// competing returns the best-ranked candidate followed by the other places
+// the name could mean: places with the same name in another country or
+// region. It returns nil when the best-ranked one is clearly meant, because
+// no other place shares its name or it is far more populous than all of them
+// (Belgrade, Serbia over Belgrade, Montana).
+func competing(candidates []Coordinates) []Coordinates {
+	top := candidates[0]
+	out := []Coordinates{top}
+	rival := 0
+	for _, c := range candidates[1:] {
+		if !strings.EqualFold(c.Name, top.Name) || (c.Country == top.Country && c.Admin1 == top.Admin1) {
+			continue
+		}
+		out = append(out, c)
+		rival = max(rival, c.Population)
+	}
+	if len(out) == 1 || (top.Population > 0 && top.Population >= clearLead*rival) {
+		return nil
+	}
+	return out
 }
 
-func loadCache() (map[string]Coordinates, error) {
-	path := cachePath()
-	file, err := os.Open(path)
-	if err != nil {
-		return make(map[string]Coordinates), nil
+// SearchLocations lists up to count candidates for a name that pass the
+// country/admin1 filters, best-ranked first. The offline index imported with
+// `goweather geocode import` is searched first; the geocoding API is only
+// called when it has no match.
+func (c *Client) SearchLocations(ctx context.Context, name string, opts GeocodeOptions, count int) ([]Coordinates, error) {
+	if count <= 0 {
+		count = DefaultGeocodeCount
+	}
+	if idx := LoadPlaceIndex(); idx != nil {
+		if found := idx.Search(name, opts, count); len(found) > 0 {
+			c.logger.Infow("Offline geocoding match", "city", name, "candidates", len(found))
+			return found, nil
+		}
 	}
-	defer file.Close()
 
-	var data map[string]Coordinates
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return make(map[string]Coordinates), nil
+	candidates, err := c.searchOnline(ctx, name, opts.Country, count)
+	if err != nil {
+		return nil, err
 	}
-	return data, nil
+	return FilterLocations(candidates, opts.Country, opts.Admin1), nil
 }
 
-func saveCache(data map[string]Coordinates) error {
-	path := cachePath()
-	file, err := os.Create(path)
+// searchOnline queries the geocoding API. A two-letter country is passed
+// upstream as an ISO code to narrow the search.
+func (c *Client) searchOnline(ctx context.Context, name, country string, count int) ([]Coordinates, error) {
+	c.logger.Infow("Calling Open-Meteo geocoding API", "city", name)
+	reqURL := fmt.Sprintf("%s/search?name=%s&count=%d", c.geocodingURL, url.QueryEscape(name), count)
+	if len(country) == 2 {
+		reqURL += "&countryCode=" + strings.ToUpper(country)
+	}
+
+	resp, err := c.get(ctx, reqURL)
 	if err != nil {
-		return err
+		c.logger.Errorw("HTTP request failed", "url", reqURL, "error", err)
+		return nil, fmt.Errorf("geocode request failed: %w", err)
 	}
-	defer file.Close()
+	defer resp.Body.Close()
 
-	enc := json.NewEncoder(file)
-	enc.SetIndent("", "  ")
-	return enc.Encode(data)
+	var geo model.GeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geo); err != nil {
+		c.logger.Errorw("Failed to decode geocoding response", "error", err)
+		return nil, decodeError(err)
+	}
+
+	out := make([]Coordinates, 0, len(geo.Results))
+	for _, r := range geo.Results {
+		out = append(out, Coordinates{
+			Name:        r.Name,
+			Latitude:    r.Latitude,
+			Longitude:   r.Longitude,
+			Country:     r.Country,
+			CountryCode: r.CountryCode,
+			Admin1:      r.Admin1,
+			Population:  r.Population,
+			Timezone:    r.Timezone,
+		})
+	}
+	return out, nil
+}
+
+// FilterLocations keeps candidates matching country (ISO code or a prefix of
+// the name) and admin1 (a prefix of the region), both case-insensitive.
+func FilterLocations(candidates []Coordinates, country, admin1 string) []Coordinates {
+	country = strings.ToLower(strings.TrimSpace(country))
+	admin1 = strings.ToLower(strings.TrimSpace(admin1))
+	var out []Coordinates
+	for _, c := range candidates {
+		if country != "" && strings.ToLower(c.CountryCode) != country &&
+			!strings.HasPrefix(strings.ToLower(c.Country), country) {
+			continue
+		}
+		if admin1 != "" && !strings.HasPrefix(strings.ToLower(c.Admin1), admin1) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
 }
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"goweather/internal/log"
+)
+
+// index file for places imported from GeoNames dumps
+const placeIndexFile = "places.gob"
+
+// PlaceIndexPath returns where `goweather geocode import` stores the offline index.
+func PlaceIndexPath() string {
+	dir, _ := os.UserCacheDir()
+	return filepath.Join(dir, "goweather", placeIndexFile)
+}
+
+// ImportGeoNames parses a GeoNames cities dump (cities15000.txt or similar,
+// optionally zipped). When an admin1CodesASCII.txt sits next to it, region
+// codes are replaced by their names; a countryInfo.txt next to it names the
+// countries the built-in city list does not cover.
+func ImportGeoNames(path string) ([]Place, error) {
+	r, closeFn, err := openDump(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	admin1 := map[string]string{}
+	if f, err := os.Open(filepath.Join(filepath.Dir(path), "admin1CodesASCII.txt")); err == nil {
+		admin1, err = readAdmin1Codes(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	countries := bundledCountryNames()
+	if f, err := os.Open(filepath.Join(filepath.Dir(path), "countryInfo.txt")); err == nil {
+		err = readCountryInfo(f, countries)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return readGeoNames(r, admin1, countries)
+}
+
+// openDump opens a plain dump or the first .txt entry of a zip archive.
+func openDump(path string) (io.Reader, func() error, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.Close, nil
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, zf := range zr.File {
+		if strings.HasSuffix(zf.Name, ".txt") {
+			rc, err := zf.Open()
+			if err != nil {
+				zr.Close()
+				return nil, nil, err
+			}
+			return rc, func() error { rc.Close(); return zr.Close() }, nil
+		}
+	}
+	zr.Close()
+	return nil, nil, fmt.Errorf("%s: no .txt file in archive", path)
+}
+
+// readGeoNames parses the tab-separated GeoNames "geoname" table.
+func readGeoNames(r io.Reader, admin1, countries map[string]string) ([]Place, error) {
+	var places []Place
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		f := strings.Split(sc.Text(), "\t")
+		if len(f) < 19 {
+			return nil, fmt.Errorf("line %d: expected 19 tab-separated fields, got %d", line, len(f))
+		}
+		lat, err := strconv.ParseFloat(f[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: latitude: %w", line, err)
+		}
+		lon, err := strconv.ParseFloat(f[5], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: longitude: %w", line, err)
+		}
+		pop, _ := strconv.Atoi(f[14])
+
+		p := Place{
+			Name:        f[1],
+			ASCIIName:   f[2],
+			CountryCode: f[8],
+			Country:     countries[f[8]],
+			Admin1:      f[10],
+			Latitude:    lat,
+			Longitude:   lon,
+			Population:  pop,
+			Timezone:    f[17],
+		}
+		if name, ok := admin1[f[8]+"."+f[10]]; ok {
+			p.Admin1 = name
+		}
+		if p.Country == "" {
+			p.Country = p.CountryCode
+		}
+		if f[3] != "" {
+			p.AlternateNames = strings.Split(f[3], ",")
+		}
+		places = append(places, p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(places) == 0 {
+		return nil, fmt.Errorf("no places found")
+	}
+	return places, nil
+}
+
+// readAdmin1Codes parses admin1CodesASCII.txt ("US.CA<TAB>California<TAB>...").
+func readAdmin1Codes(r io.Reader) (map[string]string, error) {
+	codes := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Split(sc.Text(), "\t")
+		if len(f) >= 2 {
+			codes[f[0]] = f[1]
+		}
+	}
+	return codes, sc.Err()
+}
+
+// readCountryInfo adds the names in countryInfo.txt ("RS<TAB>SRB<TAB>688<TAB>RI<TAB>Serbia<TAB>...")
+// to countries, keyed by ISO code.
+func readCountryInfo(r io.Reader, countries map[string]string) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "#") {
+			continue
+		}
+		f := strings.Split(sc.Text(), "\t")
+		if len(f) >= 5 && f[0] != "" && f[4] != "" {
+			countries[f[0]] = f[4]
+		}
+	}
+	return sc.Err()
+}
+
+// bundledCountryNames maps ISO codes to names using the built-in city list.
+func bundledCountryNames() map[string]string {
+	names := make(map[string]string)
+	for _, p := range BundledPlaces() {
+		names[p.CountryCode] = p.Country
+	}
+	return names
+}
+
+// SavePlaceIndex writes imported places to the offline index.
+func SavePlaceIndex(places []Place) error {
+	path := PlaceIndexPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), placeIndexFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(places); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// PlaceIndex is the searchable offline index.
+type PlaceIndex struct {
+	places []Place
+	keys   [][]string // normalized name, ASCII name and alternates per place
+}
+
+var (
+	placeIndexOnce sync.Once
+	placeIndex     *PlaceIndex
+)
+
+// LoadPlaceIndex returns the imported offline index, or nil when none has
+// been imported. It is read once per process.
+func LoadPlaceIndex() *PlaceIndex {
+	placeIndexOnce.Do(func() {
+		f, err := os.Open(PlaceIndexPath())
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		var places []Place
+		if err := gob.NewDecoder(f).Decode(&places); err != nil {
+			if log.Logger != nil {
+				log.Logger.Warnw("Ignoring unreadable offline place index, run `goweather geocode import` again",
+					"path", PlaceIndexPath(), "error", err)
+			}
+			return
+		}
+		placeIndex = NewPlaceIndex(places)
+	})
+	return placeIndex
+}
+
+// NewPlaceIndex prepares places for searching.
+func NewPlaceIndex(places []Place) *PlaceIndex {
+	idx := &PlaceIndex{places: places, keys: make([][]string, len(places))}
+	for i, p := range places {
+		// name and ASCII name first: only those are fuzzy-matched
+		keys := []string{normalizeName(p.Name), normalizeName(p.ASCIIName)}
+		for _, alt := range p.AlternateNames {
+			if k := normalizeName(alt); k != "" {
+				keys = append(keys, k)
+			}
+		}
+		idx.keys[i] = keys
+	}
+	return idx
+}
+
+// Places returns every place in the index.
+func (idx *PlaceIndex) Places() []Place { return idx.places }
+
+// match tiers, best first
+const (
+	matchExact = iota
+	matchPrefix
+	matchFuzzy
+	matchNone
+)
+
+// Search finds places by name with the country/admin1 filters. Exact name
+// matches win over prefix matches, which win over fuzzy (typo) matches;
+// within a tier larger places rank first.
+func (idx *PlaceIndex) Search(name string, opts GeocodeOptions, count int) []Coordinates {
+	q := normalizeName(name)
+	if q == "" {
+		return nil
+	}
+
+	type hit struct {
+		place Place
+		tier  int
+	}
+	var hits []hit
+	best := matchNone
+	for i, p := range idx.places {
+		tier := idx.matchTier(i, q)
+		if tier == matchNone || tier > best {
+			continue
+		}
+		if len(FilterLocations([]Coordinates{p.Coordinates()}, opts.Country, opts.Admin1)) == 0 {
+			continue
+		}
+		if tier < best {
+			best, hits = tier, hits[:0]
+		}
+		hits = append(hits, hit{p, tier})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].place.Population > hits[j].place.Population
+	})
+	if count > 0 && len(hits) > count {
+		hits = hits[:count]
+	}
+	out := make([]Coordinates, len(hits))
+	for i, h := range hits {
+		out[i] = h.place.Coordinates()
+	}
+	return out
+}
+
+func (idx *PlaceIndex) matchTier(i int, q string) int {
+	tier := matchNone
+	for k, key := range idx.keys[i] {
+		switch {
+		case key == q:
+			return matchExact
+		case strings.HasPrefix(key, q):
+			tier = min(tier, matchPrefix)
+		case k < 2 && key != "" && tier > matchFuzzy && withinEdits(key, q, fuzzyEdits(q)):
+			tier = matchFuzzy
+		}
+	}
+	return tier
+}
+
+// normalizeName lowercases and keeps only letters and digits, so
+// "New York", "new-york" and "newyork" compare equal.
+func normalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fuzzyEdits is how many typos a query of this length may contain.
+func fuzzyEdits(q string) int {
+	switch n := len([]rune(q)); {
+	case n <= 3:
+		return 0
+	case n <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// withinEdits reports whether the Levenshtein distance between a and b is at most max.
+func withinEdits(a, b string, max int) bool {
+	if max == 0 {
+		return a == b
+	}
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return false
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return false
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)] <= max
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IconSet selects the symbols shown next to condition descriptions.
+type IconSet string
+
+const (
+	IconsEmoji    IconSet = "emoji"
+	IconsNerdFont IconSet = "nerdfont" // Nerd Font weather glyphs
+	IconsASCII    IconSet = "ascii"
+	IconsNone     IconSet = "none"
+)
+
+// IconSets lists the supported icon sets.
+var IconSets = []IconSet{IconsEmoji, IconsNerdFont, IconsASCII, IconsNone}
+
+// ParseIconSet returns the icon set called name ("" selects emoji).
+func ParseIconSet(name string) (IconSet, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return IconsEmoji, nil
+	}
+	for _, s := range IconSets {
+		if IconSet(name) == s {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown icon set %q (want emoji, nerdfont, ascii or none)", name)
+}
+
+// icons holds one symbol per condition, indexed by Condition.
+var icons = map[IconSet][]string{
+	IconsEmoji: {
+		ConditionUnknown:          "🌈",
+		ConditionClear:            "☀️",
+		ConditionPartlyCloudy:     "🌤️",
+		ConditionOvercast:         "☁️",
+		ConditionFog:              "🌫️",
+		ConditionDrizzle:          "🌦️",
+		ConditionFreezingDrizzle:  "🌧️",
+		ConditionRain:             "🌧️",
+		ConditionFreezingRain:     "🌧️",
+		ConditionSnow:             "🌨️",
+		ConditionSnowGrains:       "❄️",
+		ConditionRainShowers:      "🌧️",
+		ConditionSnowShowers:      "🌨️",
+		ConditionThunderstorm:     "⛈️",
+		ConditionThunderstormHail: "🌩️",
+	},
+	IconsNerdFont: {
+		ConditionUnknown:          "\ue374", // nf-weather-na
+		ConditionClear:            "\ue30d", // nf-weather-day_sunny
+		ConditionPartlyCloudy:     "\ue302", // nf-weather-day_cloudy
+		ConditionOvercast:         "\ue312", // nf-weather-cloudy
+		ConditionFog:              "\ue313", // nf-weather-fog
+		ConditionDrizzle:          "\ue31b", // nf-weather-sprinkle
+		ConditionFreezingDrizzle:  "\ue316", // nf-weather-rain_mix
+		ConditionRain:             "\ue318", // nf-weather-rain
+		ConditionFreezingRain:     "\ue316", // nf-weather-rain_mix
+		ConditionSnow:             "\ue31a", // nf-weather-snow
+		ConditionSnowGrains:       "\ue36f", // nf-weather-snowflake_cold
+		ConditionRainShowers:      "\ue319", // nf-weather-showers
+		ConditionSnowShowers:      "\ue31a", // nf-weather-snow
+		ConditionThunderstorm:     "\ue31d", // nf-weather-thunderstorm
+		ConditionThunderstormHail: "\ue314", // nf-weather-hail
+	},
+	IconsASCII: {
+		ConditionUnknown:          "??",
+		ConditionClear:            "()",
+		ConditionPartlyCloudy:     "o~",
+		ConditionOvercast:         "~~",
+		ConditionFog:              "==",
+		ConditionDrizzle:          "..",
+		ConditionFreezingDrizzle:  ".*",
+		ConditionRain:             "//",
+		ConditionFreezingRain:     "/*",
+		ConditionSnow:             "**",
+		ConditionSnowGrains:       "*.",
+		ConditionRainShowers:      "/~",
+		ConditionSnowShowers:      "*~",
+		ConditionThunderstorm:     "/!",
+		ConditionThunderstormHail: "!o",
+	},
+}
+
+// Icon returns the condition's symbol in set; IconsNone and unknown sets
+// return "".
+func (c Condition) Icon(set IconSet) string {
+	symbols := icons[set]
+	if int(c) < 0 || int(c) >= len(symbols) {
+		return ""
+	}
+	return symbols[c]
+}
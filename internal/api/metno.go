@@ -0,0 +1,374 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// DefaultMetNorwayURL is the public MET Norway locationforecast 2.0 API.
+const DefaultMetNorwayURL = "https://api.met.no/weatherapi/locationforecast/2.0"
+
+// MetNorway is the Provider backed by MET Norway's locationforecast 2.0 API.
+// The API only speaks metric units, so values are converted locally, and daily
+// values are aggregated per UTC date since it only returns a single timeseries.
+type MetNorway struct {
+	client *Client
+}
+
+// NewMetNorway creates a MET Norway provider that sends requests through client.
+// MET Norway's terms require an identifying User-Agent, which Client always sends.
+func NewMetNorway(client *Client) *MetNorway {
+	return &MetNorway{client: client}
+}
+
+func (m *MetNorway) Name() string { return ProviderMetNorway }
+
+// locationforecast "complete" response (only the fields goweather uses)
+type metForecast struct {
+	Geometry struct {
+		Coordinates []float64 `json:"coordinates"` // lon, lat, altitude
+	} `json:"geometry"`
+	Properties struct {
+		Timeseries []metStep `json:"timeseries"`
+	} `json:"properties"`
+}
+
+type metStep struct {
+	Time string `json:"time"`
+	Data struct {
+		Instant struct {
+			Details metDetails `json:"details"`
+		} `json:"instant"`
+		Next1Hours  *metPeriod `json:"next_1_hours"`
+		Next6Hours  *metPeriod `json:"next_6_hours"`
+		Next12Hours *metPeriod `json:"next_12_hours"`
+	} `json:"data"`
+}
+
+type metPeriod struct {
+	Summary struct {
+		SymbolCode string `json:"symbol_code"`
+	} `json:"summary"`
+	Details metDetails `json:"details"`
+}
+
+type metDetails struct {
+	AirPressure                float64 `json:"air_pressure_at_sea_level"`
+	AirTemperature             float64 `json:"air_temperature"`
+	RelativeHumidity           float64 `json:"relative_humidity"`
+	WindFromDirection          float64 `json:"wind_from_direction"`
+	WindSpeed                  float64 `json:"wind_speed"`
+	AirTemperatureMax          float64 `json:"air_temperature_max"`
+	AirTemperatureMin          float64 `json:"air_temperature_min"`
+	PrecipitationAmount        float64 `json:"precipitation_amount"`
+	ProbabilityOfPrecipitation float64 `json:"probability_of_precipitation"`
+
+	all map[string]float64 // every detail, for optional variables
+}
+
+func (d *metDetails) UnmarshalJSON(data []byte) error {
+	type plain metDetails
+	if err := json.Unmarshal(data, (*plain)(d)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &d.all)
+}
+
+// value looks up a detail field in the instant data, then in the 1-hour and
+// 6-hour periods (where precipitation values live).
+func (s *metStep) value(field string) (float64, bool) {
+	if v, ok := s.Data.Instant.Details.all[field]; ok {
+		return v, true
+	}
+	for _, p := range []*metPeriod{s.Data.Next1Hours, s.Data.Next6Hours} {
+		if p == nil {
+			continue
+		}
+		if v, ok := p.Details.all[field]; ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// symbol returns the symbol code of the shortest forecast period available.
+func (s *metStep) symbol() string {
+	for _, p := range []*metPeriod{s.Data.Next1Hours, s.Data.Next6Hours, s.Data.Next12Hours} {
+		if p != nil {
+			return p.Summary.SymbolCode
+		}
+	}
+	return ""
+}
+
+// metUnits are the units MET Norway responds with.
+var metUnits = units.Units{
+	Temperature:   units.Celsius,
+	WindSpeed:     units.MS,
+	Precipitation: units.MM,
+	Pressure:      units.HPa,
+}
+
+func (m *MetNorway) fetch(ctx context.Context, lat, lon float64) (*metForecast, error) {
+	url := fmt.Sprintf(
+		"%s/complete?lat=%.4f&lon=%.4f",
+		m.client.forecastBase(DefaultMetNorwayURL), lat, lon)
+
+	resp, err := m.client.get(ctx, url)
+	if err != nil {
+		m.client.logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var f metForecast
+	if err := json.NewDecoder(resp.Body).Decode(&f); err != nil {
+		m.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+	if len(f.Properties.Timeseries) == 0 {
+		return nil, fmt.Errorf("%w: empty timeseries from MET Norway", ErrDecode)
+	}
+	return &f, nil
+}
+
+// supportedVariables drops (and logs) the optional variables MET Norway has no data for.
+func (m *MetNorway) supportedVariables(vars []string) []Variable {
+	var out []Variable
+	for _, name := range vars {
+		v := LookupVariable(name)
+		if v.metNorway == "" {
+			m.client.logger.Warnw("Variable not supported by provider", "provider", m.Name(), "variable", name)
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Current returns the first (present-hour) step of the MET Norway timeseries.
+func (m *MetNorway) Current(ctx context.Context, lat, lon float64, u units.Units, vars []string) (*model.WeatherResponse, error) {
+	m.client.logger.Infow("Requesting current weather", "provider", m.Name(), "lat", lat, "lon", lon, "vars", vars)
+
+	f, err := m.fetch(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	step := f.Properties.Timeseries[0]
+	d := step.Data.Instant.Details
+
+	var w model.WeatherResponse
+	w.Latitude, w.Longitude = lat, lon
+	w.Current.Time = step.Time
+	w.Current.Temperature = d.AirTemperature
+	w.Current.Humidity = d.RelativeHumidity
+	w.Current.Windspeed = d.WindSpeed
+	w.Current.Winddirection = d.WindFromDirection
+	w.Current.Pressure = d.AirPressure
+	w.Current.Weathercode = conditionFromMetSymbol(step.symbol()).WMOCode()
+	units.ConvertCurrent(&w, metUnits, u)
+
+	if extra := m.supportedVariables(vars); len(extra) > 0 {
+		w.Variables = make(model.Values, len(extra))
+		w.Units.Variables = make(map[string]string, len(extra))
+		for _, v := range extra {
+			if val, ok := step.value(v.metNorway); ok {
+				w.Variables[v.Name] = v.Quantity.Convert(val, metUnits, u)
+			}
+			w.Units.Variables[v.Name] = v.Quantity.Label(u)
+		}
+	}
+
+	m.client.logger.Infow("Weather data retrieved",
+		"provider", m.Name(),
+		"temperature", w.Current.Temperature,
+		"wind", w.Current.Windspeed,
+		"pressure", w.Current.Pressure,
+		"humidity", w.Current.Humidity)
+
+	return &w, nil
+}
+
+// Hourly returns the hourly part of the timeseries (MET Norway switches to
+// 6-hour steps after roughly 60 hours).
+func (m *MetNorway) Hourly(ctx context.Context, lat, lon float64, days int, u units.Units, vars []string) (*model.HourlyForecast, error) {
+	days = clampDays(days)
+	m.client.logger.Infow("Requesting hourly forecast", "provider", m.Name(), "lat", lat, "lon", lon, "days", days, "vars", vars)
+
+	f, err := m.fetch(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	var h model.HourlyForecast
+	h.Latitude, h.Longitude = lat, lon
+	extra := m.supportedVariables(vars)
+	if len(extra) > 0 {
+		h.Series = make(map[string]model.Series, len(extra))
+	}
+
+	var cutoff time.Time
+	for _, step := range f.Properties.Timeseries {
+		t, err := time.Parse(time.RFC3339, step.Time)
+		if err != nil || step.Data.Next1Hours == nil {
+			continue
+		}
+		if cutoff.IsZero() {
+			cutoff = t.Add(time.Duration(days) * 24 * time.Hour)
+		}
+		if !t.Before(cutoff) {
+			break
+		}
+
+		d := step.Data.Instant.Details
+		h.Hourly.Time = append(h.Hourly.Time, step.Time)
+		h.Hourly.Temperature = append(h.Hourly.Temperature, d.AirTemperature)
+		h.Hourly.Humidity = append(h.Hourly.Humidity, d.RelativeHumidity)
+		h.Hourly.Windspeed = append(h.Hourly.Windspeed, d.WindSpeed)
+		h.Hourly.Winddirection = append(h.Hourly.Winddirection, d.WindFromDirection)
+		h.Hourly.Pressure = append(h.Hourly.Pressure, d.AirPressure)
+		h.Hourly.Weathercode = append(h.Hourly.Weathercode, conditionFromMetSymbol(step.symbol()).WMOCode())
+		for _, v := range extra {
+			val, ok := step.value(v.metNorway)
+			if !ok {
+				val = math.NaN()
+			}
+			h.Series[v.Name] = append(h.Series[v.Name], v.Quantity.Convert(val, metUnits, u))
+		}
+	}
+
+	units.ConvertHourly(&h, metUnits, u)
+	if len(extra) > 0 {
+		h.Units.Variables = make(map[string]string, len(extra))
+		for _, v := range extra {
+			h.Units.Variables[v.Name] = v.Quantity.Label(u)
+		}
+	}
+
+	m.client.logger.Infow("Hourly data retrieved", "provider", m.Name(), "records", len(h.Hourly.Time))
+	return &h, nil
+}
+
+// Daily aggregates the timeseries per UTC date. MET Norway has no sunrise or
+// sunset in locationforecast, so those fields are left empty.
+func (m *MetNorway) Daily(ctx context.Context, lat, lon float64, days int, u units.Units) (*model.DailyForecast, error) {
+	days = clampDays(days)
+	m.client.logger.Infow("Requesting daily forecast", "provider", m.Name(), "lat", lat, "lon", lon, "days", days)
+
+	f, err := m.fetch(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	var d model.DailyForecast
+	d.Latitude, d.Longitude = lat, lon
+	d.Timezone = "UTC"
+
+	idx := -1
+	middayDistance := math.MaxFloat64
+	for _, step := range f.Properties.Timeseries {
+		t, err := time.Parse(time.RFC3339, step.Time)
+		if err != nil {
+			continue
+		}
+		t = t.UTC()
+		date := t.Format("2006-01-02")
+
+		if idx < 0 || d.Daily.Time[idx] != date {
+			if len(d.Daily.Time) == days {
+				break
+			}
+			idx++
+			middayDistance = math.MaxFloat64
+			d.Daily.Time = append(d.Daily.Time, date)
+			d.Daily.TemperatureMax = append(d.Daily.TemperatureMax, math.Inf(-1))
+			d.Daily.TemperatureMin = append(d.Daily.TemperatureMin, math.Inf(1))
+			d.Daily.PrecipitationSum = append(d.Daily.PrecipitationSum, 0)
+			d.Daily.PrecipitationProbability = append(d.Daily.PrecipitationProbability, 0)
+			d.Daily.WindspeedMax = append(d.Daily.WindspeedMax, 0)
+			d.Daily.Sunrise = append(d.Daily.Sunrise, "")
+			d.Daily.Sunset = append(d.Daily.Sunset, "")
+			d.Daily.Weathercode = append(d.Daily.Weathercode, ConditionUnknown.WMOCode())
+		}
+
+		inst := step.Data.Instant.Details
+		d.Daily.TemperatureMax[idx] = math.Max(d.Daily.TemperatureMax[idx], inst.AirTemperature)
+		d.Daily.TemperatureMin[idx] = math.Min(d.Daily.TemperatureMin[idx], inst.AirTemperature)
+		d.Daily.WindspeedMax[idx] = math.Max(d.Daily.WindspeedMax[idx], inst.WindSpeed)
+
+		// Precipitation comes from the 1-hour period while available, then from 6-hour periods
+		period := step.Data.Next1Hours
+		if period == nil {
+			period = step.Data.Next6Hours
+			if period != nil {
+				d.Daily.TemperatureMax[idx] = math.Max(d.Daily.TemperatureMax[idx], period.Details.AirTemperatureMax)
+				d.Daily.TemperatureMin[idx] = math.Min(d.Daily.TemperatureMin[idx], period.Details.AirTemperatureMin)
+			}
+		}
+		if period != nil {
+			d.Daily.PrecipitationSum[idx] += period.Details.PrecipitationAmount
+			d.Daily.PrecipitationProbability[idx] = math.Max(d.Daily.PrecipitationProbability[idx], period.Details.ProbabilityOfPrecipitation)
+		}
+
+		// The day's condition is the one forecast closest to midday
+		if dist := math.Abs(float64(t.Hour()) - 12); dist < middayDistance {
+			middayDistance = dist
+			d.Daily.Weathercode[idx] = conditionFromMetSymbol(step.symbol()).WMOCode()
+		}
+	}
+
+	units.ConvertDaily(&d, metUnits, u)
+
+	m.client.logger.Infow("Daily data retrieved", "provider", m.Name(), "records", len(d.Daily.Time))
+	return &d, nil
+}
+
+// conditionFromMetSymbol maps a MET Norway symbol code (e.g. "lightrainshowers_day")
+// to a Condition.
+func conditionFromMetSymbol(symbol string) Condition {
+	// Strip the _day/_night/_polartwilight variant
+	if i := strings.IndexByte(symbol, '_'); i >= 0 {
+		symbol = symbol[:i]
+	}
+
+	switch {
+	case symbol == "":
+		return ConditionUnknown
+	case strings.Contains(symbol, "thunder"):
+		return ConditionThunderstorm
+	case strings.Contains(symbol, "snowshowers"):
+		return ConditionSnowShowers
+	case strings.Contains(symbol, "rainshowers"):
+		return ConditionRainShowers
+	// WMO codes have no sleet, so wintry mixes map to the closest codes
+	// Open-Meteo emits: sleet showers to snow showers, sleet to freezing rain.
+	case strings.Contains(symbol, "sleetshowers"):
+		return ConditionSnowShowers
+	case strings.Contains(symbol, "sleet"):
+		return ConditionFreezingRain
+	case strings.Contains(symbol, "snow"):
+		return ConditionSnow
+	case strings.Contains(symbol, "drizzle"):
+		return ConditionDrizzle
+	case strings.Contains(symbol, "rain"):
+		return ConditionRain
+	case symbol == "fog":
+		return ConditionFog
+	case symbol == "cloudy":
+		return ConditionOvercast
+	case symbol == "partlycloudy", symbol == "fair":
+		return ConditionPartlyCloudy
+	case symbol == "clearsky":
+		return ConditionClear
+	default:
+		return ConditionUnknown
+	}
+}
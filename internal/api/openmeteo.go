@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// DefaultOpenMeteoURL is the public Open-Meteo forecast API.
+const DefaultOpenMeteoURL = "https://api.open-meteo.com/v1"
+
+// OpenMeteo is the Provider backed by the Open-Meteo forecast API.
+type OpenMeteo struct {
+	client *Client
+}
+
+// NewOpenMeteo creates an Open-Meteo provider that sends requests through client.
+func NewOpenMeteo(client *Client) *OpenMeteo {
+	return &OpenMeteo{client: client}
+}
+
+func (o *OpenMeteo) Name() string { return ProviderOpenMeteo }
+
+// Current fetches current weather data plus the optional variables vars with retry/backoff.
+func (o *OpenMeteo) Current(ctx context.Context, lat, lon float64, u units.Units, vars []string) (*model.WeatherResponse, error) {
+	url := fmt.Sprintf(
+		"%s/forecast?latitude=%.4f&longitude=%.4f&current=%s%s",
+		o.client.forecastBase(DefaultOpenMeteoURL), lat, lon, variableList(vars), unitParams(u))
+
+	o.client.logger.Infow("Requesting current weather", "lat", lat, "lon", lon, "vars", vars)
+
+	resp, err := o.client.get(ctx, url)
+	if err != nil {
+		o.client.logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var w model.WeatherResponse
+	var extras openMeteoExtras
+	if err := decodeOpenMeteo(resp.Body, &w, &extras); err != nil {
+		o.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+	units.ConvertCurrent(&w, fetchedUnits(u), u)
+	if err := extras.current(&w, vars, u); err != nil {
+		o.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+
+	o.client.logger.Infow("Weather data retrieved",
+		"temperature", w.Current.Temperature,
+		"wind", w.Current.Windspeed,
+		"pressure", w.Current.Pressure,
+		"humidity", w.Current.Humidity)
+
+	return &w, nil
+}
+
+// Hourly fetches hourly forecast data plus the optional variables vars for the
+// given number of days with retry/backoff.
+func (o *OpenMeteo) Hourly(ctx context.Context, lat, lon float64, days int, u units.Units, vars []string) (*model.HourlyForecast, error) {
+	days = clampDays(days)
+	url := fmt.Sprintf(
+		"%s/forecast?latitude=%.4f&longitude=%.4f&hourly=%s&forecast_days=%d%s",
+		o.client.forecastBase(DefaultOpenMeteoURL), lat, lon, variableList(vars), days, unitParams(u))
+
+	o.client.logger.Infow("Requesting hourly forecast", "lat", lat, "lon", lon, "days", days, "vars", vars)
+
+	resp, err := o.client.get(ctx, url)
+	if err != nil {
+		o.client.logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var h model.HourlyForecast
+	var extras openMeteoExtras
+	if err := decodeOpenMeteo(resp.Body, &h, &extras); err != nil {
+		o.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+	units.ConvertHourly(&h, fetchedUnits(u), u)
+	if err := extras.hourly(&h, vars, u); err != nil {
+		o.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+
+	o.client.logger.Infow("Hourly data retrieved", "records", len(h.Hourly.Time))
+	return &h, nil
+}
+
+// Daily fetches a daily forecast for up to MaxForecastDays days with retry/backoff.
+func (o *OpenMeteo) Daily(ctx context.Context, lat, lon float64, days int, u units.Units) (*model.DailyForecast, error) {
+	days = clampDays(days)
+	url := fmt.Sprintf(
+		"%s/forecast?latitude=%.4f&longitude=%.4f&daily=temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,windspeed_10m_max,sunrise,sunset,weathercode&timezone=auto&forecast_days=%d%s",
+		o.client.forecastBase(DefaultOpenMeteoURL), lat, lon, days, unitParams(u))
+
+	o.client.logger.Infow("Requesting daily forecast", "lat", lat, "lon", lon, "days", days)
+
+	resp, err := o.client.get(ctx, url)
+	if err != nil {
+		o.client.logger.Errorw("HTTP request failed after retries", "url", url, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var d model.DailyForecast
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		o.client.logger.Errorw("JSON decode failed", "error", err)
+		return nil, decodeError(err)
+	}
+	units.ConvertDaily(&d, fetchedUnits(u), u)
+
+	o.client.logger.Infow("Daily data retrieved", "records", len(d.Daily.Time))
+	return &d, nil
+}
+
+// unitParams returns the query parameters asking Open-Meteo for the units it
+// supports natively.
+func unitParams(u units.Units) string {
+	return fmt.Sprintf("&temperature_unit=%s&wind_speed_unit=%s&precipitation_unit=%s",
+		u.Temperature, u.WindSpeed, u.Precipitation)
+}
+
+// fetchedUnits returns the units Open-Meteo responds with for a request made
+// with unitParams(u): everything as requested except pressure, which is always hPa.
+func fetchedUnits(u units.Units) units.Units {
+	u.Pressure = units.HPa
+	return u
+}
+
+// baseVariables are the Open-Meteo variables behind the fixed model fields.
+const baseVariables = "temperature_2m,relative_humidity_2m,windspeed_10m,winddirection_10m,weathercode,surface_pressure"
+
+// variableList appends the optional variables to the base variable list.
+func variableList(vars []string) string {
+	return strings.Join(append([]string{baseVariables}, vars...), ",")
+}
+
+// openMeteoExtras holds the raw sections of a response that carry optional variables.
+type openMeteoExtras struct {
+	Current      map[string]json.RawMessage `json:"current"`
+	CurrentUnits map[string]string          `json:"current_units"`
+	Hourly       map[string]json.RawMessage `json:"hourly"`
+	HourlyUnits  map[string]string          `json:"hourly_units"`
+}
+
+// decodeOpenMeteo decodes a response body into both the typed model and the raw extras.
+func decodeOpenMeteo(r io.Reader, v any, extras *openMeteoExtras) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, extras)
+}
+
+// current copies the requested optional variables into w.
+func (e *openMeteoExtras) current(w *model.WeatherResponse, vars []string, u units.Units) error {
+	if len(vars) == 0 {
+		return nil
+	}
+	w.Variables = make(model.Values, len(vars))
+	w.Units.Variables = make(map[string]string, len(vars))
+	for _, name := range vars {
+		raw, ok := e.Current[name]
+		if !ok {
+			continue
+		}
+		var v *float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("variable %s: %v", name, err)
+		}
+		if v != nil {
+			w.Variables[name] = *v
+		}
+		w.Units.Variables[name] = variableLabel(name, u, e.CurrentUnits[name])
+	}
+	return nil
+}
+
+// hourly copies the requested optional variable series into h.
+func (e *openMeteoExtras) hourly(h *model.HourlyForecast, vars []string, u units.Units) error {
+	if len(vars) == 0 {
+		return nil
+	}
+	h.Series = make(map[string]model.Series, len(vars))
+	h.Units.Variables = make(map[string]string, len(vars))
+	for _, name := range vars {
+		raw, ok := e.Hourly[name]
+		if !ok {
+			continue
+		}
+		var series model.Series
+		if err := json.Unmarshal(raw, &series); err != nil {
+			return fmt.Errorf("variable %s: %v", name, err)
+		}
+		h.Series[name] = series
+		h.Units.Variables[name] = variableLabel(name, u, e.HourlyUnits[name])
+	}
+	return nil
+}
+
+// variableLabel uses goweather's own label for quantities it converts and
+// the label Open-Meteo reported for everything else.
+func variableLabel(name string, u units.Units, upstream string) string {
+	v := LookupVariable(name)
+	if upstream != "" && (v.Quantity == units.QuantityNone || v.Quantity == units.QuantityDistance) {
+		return upstream
+	}
+	return v.Quantity.Label(u)
+}
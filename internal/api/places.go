@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// Place is a named location in an offline city list.
+type Place struct {
+	Name           string
+	ASCIIName      string
+	AlternateNames []string
+	CountryCode    string
+	Country        string
+	Admin1         string
+	Latitude       float64
+	Longitude      float64
+	Population     int
+	Timezone       string
+}
+
+// Coordinates converts a place into a geocoding result.
+func (p Place) Coordinates() Coordinates {
+	return Coordinates{
+		Name:        p.Name,
+		Latitude:    p.Latitude,
+		Longitude:   p.Longitude,
+		Country:     p.Country,
+		CountryCode: p.CountryCode,
+		Admin1:      p.Admin1,
+		Population:  p.Population,
+		Timezone:    p.Timezone,
+	}
+}
+
+// bundledCities is a small list of capitals and large cities that keeps
+// reverse geocoding working without network access or an imported index.
+//
+//go:embed data/cities.csv
+var bundledCities []byte
+
+var (
+	bundledOnce   sync.Once
+	bundledPlaces []Place
+)
+
+// BundledPlaces returns the built-in city list.
+func BundledPlaces() []Place {
+	bundledOnce.Do(func() {
+		r := csv.NewReader(bytes.NewReader(bundledCities))
+		rows, err := r.ReadAll()
+		if err != nil {
+			panic(fmt.Sprintf("api: bundled city list: %v", err))
+		}
+		for _, row := range rows[1:] {
+			lat, _ := strconv.ParseFloat(row[4], 64)
+			lon, _ := strconv.ParseFloat(row[5], 64)
+			pop, _ := strconv.Atoi(row[6])
+			bundledPlaces = append(bundledPlaces, Place{
+				Name:        row[0],
+				CountryCode: row[1],
+				Country:     row[2],
+				Admin1:      row[3],
+				Latitude:    lat,
+				Longitude:   lon,
+				Population:  pop,
+			})
+		}
+	})
+	return bundledPlaces
+}
+
+// nearbyKm is how close a place must be for coordinates to take its name outright.
+const nearbyKm = 5
+
+// ReverseGeocode labels coordinates with the nearest place in the imported
+// index, or the bundled city list when nothing was imported, e.g. "Novi Sad"
+// or "23 km NW of Novi Sad". The returned coordinates are always the ones
+// passed in.
+func ReverseGeocode(lat, lon float64) *Coordinates {
+	coord := &Coordinates{
+		Name:      fmt.Sprintf("%.4f, %.4f", lat, lon),
+		Latitude:  lat,
+		Longitude: lon,
+	}
+	places := BundledPlaces()
+	if idx := LoadPlaceIndex(); idx != nil {
+		places = idx.Places()
+	}
+	p, dist, ok := NearestPlace(places, lat, lon)
+	if !ok {
+		return coord
+	}
+	coord.Name = p.Name
+	if dist >= nearbyKm {
+		coord.Name = fmt.Sprintf("%.0f km %s of %s", dist, compassPoint(bearing(p.Latitude, p.Longitude, lat, lon)), p.Name)
+	}
+	coord.Country = p.Country
+	coord.CountryCode = p.CountryCode
+	coord.Admin1 = p.Admin1
+	return coord
+}
+
+// NearestPlace returns the place closest to lat/lon and its distance in km.
+func NearestPlace(places []Place, lat, lon float64) (Place, float64, bool) {
+	best, bestDist := -1, math.Inf(1)
+	for i, p := range places {
+		if d := distanceKm(lat, lon, p.Latitude, p.Longitude); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return Place{}, 0, false
+	}
+	return places[best], bestDist, true
+}
+
+// ValidateCoordinates checks latitude and longitude ranges.
+func ValidateCoordinates(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v out of range (-90 to 90)", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %v out of range (-180 to 180)", lon)
+	}
+	return nil
+}
+
+const earthRadiusKm = 6371.0
+
+// distanceKm is the great-circle (haversine) distance between two points.
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	p1, p2 := radians(lat1), radians(lat2)
+	dp, dl := radians(lat2-lat1), radians(lon2-lon1)
+	a := math.Sin(dp/2)*math.Sin(dp/2) + math.Cos(p1)*math.Cos(p2)*math.Sin(dl/2)*math.Sin(dl/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// bearing is the initial compass bearing in degrees from point 1 to point 2.
+func bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	p1, p2 := radians(lat1), radians(lat2)
+	dl := radians(lon2 - lon1)
+	y := math.Sin(dl) * math.Cos(p2)
+	x := math.Cos(p1)*math.Sin(p2) - math.Sin(p1)*math.Cos(p2)*math.Cos(dl)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+func compassPoint(deg float64) string {
+	dirs := []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+	return dirs[int((deg+22.5)/45.0)%8]
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
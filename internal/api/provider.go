@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// Provider fetches forecasts from a weather backend and returns them as
+// model types with Weathercode fields expressed as WMO codes and values
+// expressed in the requested units. vars lists optional variables (canonical
+// names, see NormalizeVariables); providers skip the ones they cannot supply.
+type Provider interface {
+	Name() string
+	Current(ctx context.Context, lat, lon float64, u units.Units, vars []string) (*model.WeatherResponse, error)
+	Hourly(ctx context.Context, lat, lon float64, days int, u units.Units, vars []string) (*model.HourlyForecast, error)
+	Daily(ctx context.Context, lat, lon float64, days int, u units.Units) (*model.DailyForecast, error)
+}
+
+// Provider names accepted in config.yaml.
+const (
+	ProviderOpenMeteo = "open-meteo"
+	ProviderMetNorway = "met-norway"
+)
+
+// NewProvider returns the provider registered under name ("" selects Open-Meteo),
+// sending its requests through client.
+func NewProvider(name string, client *Client) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderOpenMeteo, "openmeteo":
+		return NewOpenMeteo(client), nil
+	case ProviderMetNorway, "metno", "met.no":
+		return NewMetNorway(client), nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider %q (want %s or %s)",
+			name, ProviderOpenMeteo, ProviderMetNorway)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goweather/internal/units"
+)
+
+// Variable describes an optional weather variable that can be requested with
+// --vars on top of the fixed model fields.
+type Variable struct {
+	Name      string // Open-Meteo variable name, used as the key in model maps
+	Label     string // column header
+	Quantity  units.Quantity
+	Aliases   []string
+	Derived   bool   // computed locally from other fields (see package metrics), never requested upstream
+	metNorway string // MET Norway detail field, empty when unsupported
+}
+
+// Variables lists the well-known variables in display order. Any other
+// Open-Meteo variable name is passed through as-is.
+var Variables = []Variable{
+	{Name: "apparent_temperature", Label: "Feels like", Quantity: units.QuantityTemperature, Aliases: []string{"feels_like", "apparent"}},
+	{Name: "dew_point_2m", Label: "Dew point", Quantity: units.QuantityTemperature, Aliases: []string{"dew_point", "dewpoint"}, metNorway: "dew_point_temperature"},
+	{Name: "precipitation", Label: "Precip", Quantity: units.QuantityPrecipitation, Aliases: []string{"precip"}, metNorway: "precipitation_amount"},
+	{Name: "precipitation_probability", Label: "Precip prob", Quantity: units.QuantityPercent, Aliases: []string{"precip_prob", "pop"}, metNorway: "probability_of_precipitation"},
+	{Name: "cloud_cover", Label: "Clouds", Quantity: units.QuantityPercent, Aliases: []string{"clouds", "cloudcover"}, metNorway: "cloud_area_fraction"},
+	{Name: "visibility", Label: "Visibility", Quantity: units.QuantityDistance},
+	{Name: "wind_gusts_10m", Label: "Gusts", Quantity: units.QuantityWindSpeed, Aliases: []string{"gusts", "wind_gusts"}, metNorway: "wind_speed_of_gust"},
+	{Name: "uv_index", Label: "UV index", Quantity: units.QuantityNone, Aliases: []string{"uv"}, metNorway: "ultraviolet_index_clear_sky"},
+	{Name: "heat_index", Label: "Heat index", Quantity: units.QuantityTemperature, Aliases: []string{"heatindex"}, Derived: true},
+	{Name: "wind_chill", Label: "Wind chill", Quantity: units.QuantityTemperature, Aliases: []string{"windchill"}, Derived: true},
+	{Name: "humidex", Label: "Humidex", Quantity: units.QuantityTemperature, Derived: true},
+	{Name: "absolute_humidity", Label: "Abs humidity", Quantity: units.QuantityNone, Aliases: []string{"abs_humidity"}, Derived: true},
+}
+
+// LookupVariable resolves a variable name or alias. Unknown names are
+// returned as a pass-through Variable labelled with the name itself.
+func LookupVariable(name string) Variable {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, v := range Variables {
+		if v.Name == name {
+			return v
+		}
+		for _, a := range v.Aliases {
+			if a == name {
+				return v
+			}
+		}
+	}
+	return Variable{Name: name, Label: name}
+}
+
+// variableName matches the names that may be passed through to Open-Meteo;
+// they go into query strings and cache keys unescaped.
+var variableName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// NormalizeVariables resolves aliases, drops empty entries and duplicates,
+// and returns the canonical names in display order. Names other than
+// lowercase letters, digits and underscores are rejected.
+func NormalizeVariables(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, n := range names {
+		for _, part := range strings.Split(n, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			v := LookupVariable(part)
+			if !variableName.MatchString(v.Name) {
+				return nil, fmt.Errorf("invalid variable name %q (want letters, digits and underscores)", strings.TrimSpace(part))
+			}
+			if !seen[v.Name] {
+				seen[v.Name] = true
+				out = append(out, v.Name)
+			}
+		}
+	}
+	SortVariables(out)
+	return out, nil
+}
+
+// VariablesKey identifies a normalized variable selection in cache keys.
+func VariablesKey(vars []string) string {
+	if len(vars) == 0 {
+		return "base"
+	}
+	return strings.Join(vars, ",")
+}
+
+// SortVariables orders names like Variables, with unknown names last in
+// alphabetical order.
+func SortVariables(names []string) {
+	rank := func(name string) int {
+		for i, v := range Variables {
+			if v.Name == name {
+				return i
+			}
+		}
+		return len(Variables)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ri, rj := rank(names[i]), rank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+}
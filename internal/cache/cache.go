@@ -8,8 +8,16 @@ import (
 	"time"
 
 	"goweather/internal/log"
+	"goweather/internal/model"
 )
 
+func init() {
+	// Items hold their data as `any`; gob needs the concrete types to persist them
+	gob.Register(&model.WeatherResponse{})
+	gob.Register(&model.HourlyForecast{})
+	gob.Register(&model.DailyForecast{})
+}
+
 type Item struct {
 	Data      any
 	Timestamp time.Time
@@ -53,9 +61,9 @@ func (c *Cache) Get(key string) (any, bool) {
 	}
 
 	if time.Since(item.Timestamp) > c.expiry {
+		// Left in place (we only hold the read lock); the next Set replaces it
 		log.Logger.Infow("Cache expired", "key", key,
 			"age", time.Since(item.Timestamp).Round(time.Second).String())
-		delete(c.items, key)
 		return nil, false
 	}
 
@@ -63,6 +71,14 @@ func (c *Cache) Get(key string) (any, bool) {
 	return item.Data, true
 }
 
+// Updated returns when key was last stored, whether or not it has expired.
+func (c *Cache) Updated(key string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[key]
+	return item.Timestamp, ok
+}
+
 // Set stores a new item and persists to disk.
 func (c *Cache) Set(key string, value any) {
 	c.mu.Lock()
@@ -75,22 +91,6 @@ func (c *Cache) Set(key string, value any) {
 	)
 }
 
-// BackgroundRefresh launches a goroutine that refreshes a key periodically.
-func (c *Cache) BackgroundRefresh(key string, refreshFn func() (any, error)) {
-	go func() {
-		for {
-			time.Sleep(c.expiry)
-			data, err := refreshFn()
-			if err != nil {
-				log.Logger.Warnw("Background refresh failed", "key", key, "error", err)
-				continue
-			}
-			c.Set(key, data)
-			log.Logger.Infow("Cache refreshed in background", "key", key)
-		}
-	}()
-}
-
 // --- internal persistence helpers ---
 
 func (c *Cache) loadFromFile() {
@@ -0,0 +1,75 @@
+package chart
+
+// canvas is a grid of dots. With braille every cell holds 2x4 dots; in
+// ASCII mode a cell is a single dot drawn as '*'.
+type canvas struct {
+	width, height int // in cells
+	cellX, cellY  int // dots per cell
+	dotsX, dotsY  int
+	dots          []bool
+}
+
+func newCanvas(width, height int, ascii bool) *canvas {
+	c := &canvas{width: width, height: height, cellX: 2, cellY: 4}
+	if ascii {
+		c.cellX, c.cellY = 1, 1
+	}
+	c.dotsX, c.dotsY = width*c.cellX, height*c.cellY
+	c.dots = make([]bool, c.dotsX*c.dotsY)
+	return c
+}
+
+func (c *canvas) set(x, y int) {
+	if x >= 0 && x < c.dotsX && y >= 0 && y < c.dotsY {
+		c.dots[y*c.dotsX+x] = true
+	}
+}
+
+// line sets the dots between two points.
+func (c *canvas) line(x0, y0, x1, y1 int) {
+	steps := max(abs(x1-x0), abs(y1-y0))
+	if steps == 0 {
+		c.set(x0, y0)
+		return
+	}
+	for i := 0; i <= steps; i++ {
+		x := x0 + (x1-x0)*i/steps
+		y := y0 + (y1-y0)*i/steps
+		c.set(x, y)
+	}
+}
+
+// brailleBits maps a dot's position within a cell to its braille bit.
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// cell returns the character for a cell, or "" when it has no dots.
+func (c *canvas) cell(col, row int) string {
+	var bits rune
+	for dy := 0; dy < c.cellY; dy++ {
+		for dx := 0; dx < c.cellX; dx++ {
+			if c.dots[(row*c.cellY+dy)*c.dotsX+col*c.cellX+dx] {
+				bits |= brailleBits[dy][dx]
+			}
+		}
+	}
+	switch {
+	case bits == 0:
+		return ""
+	case c.cellX == 1:
+		return "*"
+	default:
+		return string(0x2800 + bits)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
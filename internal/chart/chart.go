@@ -0,0 +1,209 @@
+// Package chart draws terminal line charts and sparklines.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"goweather/internal/ui"
+)
+
+// Options controls how a chart is drawn.
+type Options struct {
+	Title  string
+	Unit   string // appended to the min/max labels
+	Width  int    // total width in columns, including the y axis; 0 uses $COLUMNS
+	Height int    // plot height in rows
+	Theme  ui.Theme
+	ASCII  bool // plain ASCII instead of braille and box drawing
+}
+
+// Default chart size.
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 12
+)
+
+// glyphs are the characters of the axes and separators.
+type glyphs struct {
+	axis, tick, corner, rule, ruleMark, separator string
+}
+
+var (
+	unicodeGlyphs = glyphs{"│", "┤", "└", "─", "┴", "┊"}
+	asciiGlyphs   = glyphs{"|", "+", "+", "-", "+", ":"}
+)
+
+// Line draws values, one per time, as a line chart. The y axis is labelled
+// with the maximum, middle and minimum, the x axis with hours and dates, and
+// a separator marks each new day. Missing (NaN) values leave gaps.
+func Line(w io.Writer, times []time.Time, values []float64, opts Options) error {
+	theme := opts.Theme
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+		if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+			opts.Width = cols
+		}
+	}
+	if opts.Height <= 0 {
+		opts.Height = DefaultHeight
+	}
+	g := unicodeGlyphs
+	if opts.ASCII {
+		g = asciiGlyphs
+	}
+
+	if opts.Title != "" {
+		fmt.Fprintf(w, "\n%s%s%s\n", theme.Bold, opts.Title, theme.Reset)
+	}
+	lo, hi, loIdx, hiIdx := bounds(values)
+	if loIdx < 0 {
+		_, err := fmt.Fprintf(w, "%sno data%s\n\n", theme.Gray, theme.Reset)
+		return err
+	}
+	top, bottom := hi, lo
+	if top == bottom {
+		top, bottom = top+1, bottom-1
+	}
+
+	labels := []string{formatLabel(top), formatLabel((top + bottom) / 2), formatLabel(bottom)}
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l))
+	}
+	plotWidth := max(opts.Width-labelWidth-2, 10)
+
+	c := newCanvas(plotWidth, opts.Height, opts.ASCII)
+	xs := make([]int, len(values))
+	for i := range values {
+		if len(values) > 1 {
+			xs[i] = int(math.Round(float64(i) * float64(c.dotsX-1) / float64(len(values)-1)))
+		}
+	}
+	y := func(v float64) int {
+		return c.dotsY - 1 - int(math.Round((v-bottom)/(top-bottom)*float64(c.dotsY-1)))
+	}
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		if i > 0 && !math.IsNaN(values[i-1]) {
+			c.line(xs[i-1], y(values[i-1]), xs[i], y(v))
+		} else {
+			c.set(xs[i], y(v))
+		}
+	}
+
+	// Columns where a new day starts
+	separators := make(map[int]bool)
+	for i := 1; i < len(times); i++ {
+		if times[i].YearDay() != times[i-1].YearDay() {
+			separators[xs[i]/c.cellX] = true
+		}
+	}
+
+	for row := 0; row < opts.Height; row++ {
+		label, mark := "", g.axis
+		switch row {
+		case 0:
+			label, mark = labels[0], g.tick
+		case opts.Height / 2:
+			label, mark = labels[1], g.tick
+		case opts.Height - 1:
+			label, mark = labels[2], g.tick
+		}
+		fmt.Fprintf(w, "%s%*s %s%s", theme.Gray, labelWidth, label, mark, theme.Reset)
+		for col := 0; col < plotWidth; col++ {
+			switch cell := c.cell(col, row); {
+			case cell != "":
+				fmt.Fprintf(w, "%s%s%s", theme.Cyan, cell, theme.Reset)
+			case separators[col]:
+				fmt.Fprintf(w, "%s%s%s", theme.Gray, g.separator, theme.Reset)
+			default:
+				fmt.Fprint(w, " ")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	// X axis with a mark under every separator
+	var rule strings.Builder
+	for col := 0; col < plotWidth; col++ {
+		if separators[col] {
+			rule.WriteString(g.ruleMark)
+		} else {
+			rule.WriteString(g.rule)
+		}
+	}
+	fmt.Fprintf(w, "%s%*s %s%s%s\n", theme.Gray, labelWidth, "", g.corner, rule.String(), theme.Reset)
+	fmt.Fprintf(w, "%*s  %s\n", labelWidth, "", timeLabels(times, xs, c.cellX, plotWidth))
+
+	fmt.Fprintf(w, "%smin %s%s%s at %s   %smax %s%s%s at %s\n\n",
+		theme.Blue, formatLabel(lo), opts.Unit, theme.Reset, times[loIdx].Format("Mon 15:04"),
+		theme.Red, formatLabel(hi), opts.Unit, theme.Reset, times[hiIdx].Format("Mon 15:04"))
+	return nil
+}
+
+// timeLabels places a date under the first point of each day and hours in
+// between, skipping labels that would overlap.
+func timeLabels(times []time.Time, xs []int, cellX, width int) string {
+	line := []rune(strings.Repeat(" ", width))
+	used := make([]bool, width)
+	place := func(col int, label string) {
+		r := []rune(label)
+		if col+len(r) > width {
+			return
+		}
+		for i := col - 1; i <= col+len(r); i++ {
+			if i >= 0 && i < width && used[i] {
+				return
+			}
+		}
+		for i, ch := range r {
+			line[col+i] = ch
+			used[col+i] = true
+		}
+	}
+	for i, t := range times {
+		if i == 0 || t.YearDay() != times[i-1].YearDay() {
+			place(xs[i]/cellX, t.Format("Mon 02"))
+		}
+	}
+	for i, t := range times {
+		if t.Hour()%3 == 0 {
+			place(xs[i]/cellX, t.Format("15h"))
+		}
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// bounds returns the smallest and largest value and their indexes, or -1
+// indexes when every value is missing.
+func bounds(values []float64) (lo, hi float64, loIdx, hiIdx int) {
+	loIdx, hiIdx = -1, -1
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		if loIdx < 0 || v < lo {
+			lo, loIdx = v, i
+		}
+		if hiIdx < 0 || v > hi {
+			hi, hiIdx = v, i
+		}
+	}
+	return lo, hi, loIdx, hiIdx
+}
+
+// formatLabel prints whole numbers without decimals, others with one.
+func formatLabel(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
@@ -0,0 +1,60 @@
+package chart
+
+import (
+	"math"
+	"strings"
+)
+
+var (
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	sparkASCII  = []rune("_.-=+*#")
+)
+
+// Sparkline draws values as a single line of at most width characters
+// (no limit when width <= 0). Longer series are averaged into buckets;
+// missing values become spaces.
+func Sparkline(values []float64, width int, ascii bool) string {
+	if width > 0 && len(values) > width {
+		values = resample(values, width)
+	}
+	levels := sparkBlocks
+	if ascii {
+		levels = sparkASCII
+	}
+	lo, hi, idx, _ := bounds(values)
+	if idx < 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(levels[len(levels)/2])
+		default:
+			b.WriteRune(levels[int(math.Round((v-lo)/(hi-lo)*float64(len(levels)-1)))])
+		}
+	}
+	return b.String()
+}
+
+// resample averages values into n buckets, ignoring missing values.
+func resample(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		from, to := i*len(values)/n, (i+1)*len(values)/n
+		sum, count := 0.0, 0
+		for _, v := range values[from:to] {
+			if !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		out[i] = math.NaN()
+		if count > 0 {
+			out[i] = sum / float64(count)
+		}
+	}
+	return out
+}
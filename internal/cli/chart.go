@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/chart"
+	"goweather/internal/ui"
+)
+
+// Series that every hourly forecast has; anything else is an optional
+// variable fetched with --vars.
+var baseSeries = map[string][]string{
+	"temperature": {"temp", "temperature_2m"},
+	"humidity":    {"relative_humidity", "relative_humidity_2m"},
+	"wind_speed":  {"wind", "windspeed", "windspeed_10m", "wind_speed_10m"},
+	"pressure":    {"surface_pressure"},
+}
+
+// ChartVariable resolves a --var name. base reports whether the series is
+// part of every forecast; otherwise name is an optional variable.
+func ChartVariable(name string) (canonical string, base bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for canonical, aliases := range baseSeries {
+		if name == canonical {
+			return canonical, true
+		}
+		for _, a := range aliases {
+			if name == a {
+				return canonical, true
+			}
+		}
+	}
+	return api.LookupVariable(name).Name, false
+}
+
+// chartSeries returns the label, unit and values of a series of v.
+func chartSeries(v *HourlyView, name string) (label, unit string, values []float64) {
+	values = make([]float64, len(v.Hours))
+	pick := func(f func(Conditions) float64) {
+		for i, h := range v.Hours {
+			values[i] = f(h)
+		}
+	}
+	switch name {
+	case "temperature":
+		label, unit = "Temperature", v.Units.Temp
+		pick(func(c Conditions) float64 { return c.Temp })
+	case "humidity":
+		label, unit = "Humidity", "%"
+		pick(func(c Conditions) float64 { return c.Humidity })
+	case "wind_speed":
+		label, unit = "Wind speed", v.Units.Wind
+		pick(func(c Conditions) float64 { return c.Wind })
+	case "pressure":
+		label, unit = "Pressure", v.Units.Pressure
+		pick(func(c Conditions) float64 { return c.Pressure })
+	default:
+		label, unit = api.LookupVariable(name).Label, v.Units.Variables[name]
+		pick(func(c Conditions) float64 { return varValue(c.Vars, name) })
+	}
+	return label, unit, values
+}
+
+// PrintChart draws a line chart of one series of an hourly forecast.
+// width and height of 0 select the default size.
+func PrintChart(w io.Writer, v *HourlyView, name string, theme ui.Theme, width, height int) error {
+	label, unit, values := chartSeries(v, name)
+	times := make([]time.Time, len(v.Hours))
+	for i, h := range v.Hours {
+		times[i] = h.Time
+	}
+	details := v.TimeZone
+	if unit != "" {
+		details = unit + ", " + details
+	}
+	return chart.Line(w, times, values, chart.Options{
+		Title:  fmt.Sprintf("%s%s (%s)", label, forLocation(v.Location), details),
+		Unit:   withUnit("", unit),
+		Width:  width,
+		Height: height,
+		Theme:  theme,
+		ASCII:  asciiOnly(theme),
+	})
+}
+
+// asciiOnly reports whether charts should avoid Unicode: when colors are
+// off or the terminal is not UTF-8.
+func asciiOnly(theme ui.Theme) bool {
+	return theme.Reset == "" || !ui.SupportsUTF8()
+}
@@ -1,123 +1,228 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
-	"text/tabwriter"
+	"strings"
 	"time"
 
 	"goweather/internal/api"
 	"goweather/internal/cache"
+	"goweather/internal/chart"
 	"goweather/internal/config"
-	"goweather/internal/log"
 	"goweather/internal/model"
 	"goweather/internal/ui"
+	"goweather/internal/units"
 )
 
 // Reusable functions for CLI commands
 // -----------------------------------
 
-func RunBothMode(coords *api.Coordinates, c *cache.Cache, city *string, hours *int, theme ui.Theme, cfg *config.Config) {
-	curKey := fmt.Sprintf("%s_current", *city)
-	hrsKey := fmt.Sprintf("%s_hourly", *city)
+// ErrPartial marks a result where some, but not all, of the requested data
+// could be fetched. The missing parts are wrapped alongside it.
+var ErrPartial = errors.New("partial results")
 
-	currentData, _ := c.Get(curKey)
-	hourlyData, _ := c.Get(hrsKey)
+// RunBothMode renders current conditions and the hourly forecast, using cached
+// data when available. Missing halves are fetched in parallel under a shared timeout. When only one half
+// fails the other is still printed, followed by a warning, and the returned
+// error wraps ErrPartial.
+func RunBothMode(ctx context.Context, p api.Provider, u units.Units, vars []string, coords *api.Coordinates, c *cache.Cache, city *string, hours *int, timeout time.Duration, r Renderer, theme ui.Theme, cfg *config.Config) error {
+	f := &BothFetcher{Provider: p, Units: u, Vars: vars, Coords: coords, Cache: c, Key: *city, Hours: *hours}
+	current, hourly, curErr, hrsErr := f.Fetch(ctx, timeout)
+	if curErr != nil && hrsErr != nil {
+		return errors.Join(fmt.Errorf("current weather: %w", curErr), fmt.Errorf("hourly forecast: %w", hrsErr))
+	}
 
-	if currentData == nil {
-		data, err := api.GetWeather(coords.Latitude, coords.Longitude)
-		if err != nil {
-			log.Logger.Fatalw("Current fetch failed", "error", err)
-		}
-		c.Set(curKey, data)
-		currentData = data
+	var curView *CurrentView
+	var hrsView *HourlyView
+	if curErr == nil {
+		curView = NewCurrentView(current, cfg)
+	}
+	if hrsErr == nil {
+		hrsView = NewHourlyView(hourly, *hours, cfg)
+	}
+	if err := r.Both(os.Stdout, curView, hrsView); err != nil {
+		return err
 	}
 
-	if hourlyData == nil {
-		data, err := api.GetHourly(coords.Latitude, coords.Longitude)
-		if err != nil {
-			log.Logger.Fatalw("Hourly fetch failed", "error", err)
-		}
-		c.Set(hrsKey, data)
-		hourlyData = data
+	switch {
+	case curErr != nil:
+		printPartialFailure("current weather", curErr, theme)
+		return fmt.Errorf("%w: current weather: %w", ErrPartial, curErr)
+	case hrsErr != nil:
+		printPartialFailure("hourly forecast", hrsErr, theme)
+		return fmt.Errorf("%w: hourly forecast: %w", ErrPartial, hrsErr)
 	}
+	return nil
+}
+
+// printPartialFailure warns on stderr that part of the output is missing.
+func printPartialFailure(what string, err error, theme ui.Theme) {
+	fmt.Fprintf(os.Stderr, "%s⚠ Showing partial results, could not fetch the %s:%s\n", theme.Yellow, what, theme.Reset)
+	fmt.Fprintf(os.Stderr, "  %v\n\n", err)
+}
 
-	PrintCurrent(currentData.(*model.WeatherResponse), theme)
-	PrintHourly(hourlyData.(*model.HourlyForecast), theme, *hours, cfg)
+// tableRenderer writes the colored, aligned tables meant for terminals.
+type tableRenderer struct {
+	theme ui.Theme
+	opts  TableOptions
+}
 
-	// Background refresh for both
-	c.BackgroundRefresh(curKey, func() (any, error) {
-		return api.GetWeather(coords.Latitude, coords.Longitude)
-	})
-	c.BackgroundRefresh(hrsKey, func() (any, error) {
-		return api.GetHourly(coords.Latitude, coords.Longitude)
-	})
+// NewTableRenderer returns the table renderer, optionally with a sparkline
+// row summarizing each hourly column and values colored by magnitude.
+func NewTableRenderer(theme ui.Theme, opts TableOptions) Renderer {
+	return tableRenderer{theme: theme, opts: opts}
 }
 
-func PrintCurrent(weather *model.WeatherResponse, theme ui.Theme) {
-	fmt.Printf("\n%sCurrent weather:%s\n", theme.Bold, theme.Reset)
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+func (r tableRenderer) Current(out io.Writer, v *CurrentView) error {
+	theme := r.theme
+	fmt.Fprintf(out, "\n%sCurrent weather%s:%s\n", theme.Bold, forLocation(v.Location), theme.Reset)
+	w := newTableWriter(out)
 	fmt.Fprintf(w, "%s%-20s\t%-12s%s\n", theme.Bold, "Parameter", "Value", theme.Reset)
 	fmt.Fprintf(w, "%s──────────────────────\t───────────────%s\n", theme.Gray, theme.Reset)
 
-	fmt.Fprintf(w, "%sTemperature%s\t%.1f °C\n", theme.Cyan, theme.Reset, weather.Current.Temperature)
-	fmt.Fprintf(w, "%sHumidity%s\t%.0f %%\n", theme.Blue, theme.Reset, weather.Current.Humidity)
-	fmt.Fprintf(w, "%sWind speed%s\t%.1f km/h\n", theme.Yellow, theme.Reset, weather.Current.Windspeed)
-	fmt.Fprintf(w, "%sWind direction%s\t%s\n", theme.Yellow, theme.Reset, degreesToCompass(weather.Current.Winddirection))
-	fmt.Fprintf(w, "%sPressure%s\t%.0f hPa\n", theme.Green, theme.Reset, weather.Current.Pressure)
-	fmt.Fprintf(w, "%sCondition%s\t%s\n", theme.Red, theme.Reset, api.WeatherDescription(weather.Current.Weathercode))
+	un := v.Units
+	fmt.Fprintf(w, "%sTemperature%s\t%s%.1f %s%s\n", theme.Cyan, theme.Reset,
+		r.valueColor("temperature", v.Temp, un.Temp, ""), v.Temp, un.Temp, theme.Reset)
+	fmt.Fprintf(w, "%sHumidity%s\t%.0f %%\n", theme.Blue, theme.Reset, v.Humidity)
+	fmt.Fprintf(w, "%sWind speed%s\t%s%.1f %s%s\n", theme.Yellow, theme.Reset,
+		r.valueColor("wind_speed", v.Wind, un.Wind, ""), v.Wind, un.Wind, theme.Reset)
+	fmt.Fprintf(w, "%sWind direction%s\t%s\n", theme.Yellow, theme.Reset, v.Compass)
+	fmt.Fprintf(w, "%sPressure%s\t%s %s\n", theme.Green, theme.Reset, formatPressure(v.Pressure, un.Pressure), un.Pressure)
+	for _, name := range v.vars {
+		val := varValue(v.Vars, name)
+		fmt.Fprintf(w, "%s%s%s\t%s%s%s\n", theme.Blue, api.LookupVariable(name).Label, theme.Reset,
+			r.valueColor(name, val, un.Variables[name], ""), withUnit(formatValue(val), un.Variables[name]), theme.Reset)
+	}
+	fmt.Fprintf(w, "%sCondition%s\t%s\n", theme.Red, theme.Reset, description(v.Icon, v.Condition))
 	w.Flush()
-	fmt.Println()
+	fmt.Fprintln(out)
+	r.writeLegend(out, append([]string{"temperature", "wind_speed"}, v.vars...), un.unitOf)
+	return nil
 }
 
-func PrintHourly(forecast *model.HourlyForecast, theme ui.Theme, hours int, cfg *config.Config) {
-	loc := time.Local
-	locName := "Local"
-	if cfg.TimeZone != "" && cfg.TimeZone != "local" {
-		if userLoc, err := time.LoadLocation(cfg.TimeZone); err == nil {
-			loc = userLoc
-			locName = cfg.TimeZone
+func (r tableRenderer) Hourly(out io.Writer, v *HourlyView) error {
+	theme := r.theme
+	un := v.Units
+	fmt.Fprintf(out, "\n%sHourly forecast%s (%s):%s\n", theme.Bold, forLocation(v.Location), v.TimeZone, theme.Reset)
+	w := newTableWriter(out)
+	// Optional variables become extra columns before Conditions
+	extraHeader, extraRule := "", ""
+	for _, name := range v.vars {
+		extraHeader += fmt.Sprintf("%-12s\t", withUnit(api.LookupVariable(name).Label, "("+un.Variables[name]+")"))
+		extraRule += "────────────\t"
+	}
+
+	fmt.Fprintf(w, "%s%-20s\t%-12s\t%-12s\t%-12s\t%-12s\t%-16s\t%s%-16s%s\n",
+		theme.Bold, "Time", "Temp ("+un.Temp+")", "Wind ("+un.Wind+")", "Dir", "Humidity (%)", "Pressure ("+un.Pressure+")", extraHeader, "Conditions", theme.Reset)
+	fmt.Fprintf(w, "%s──────────────────────\t────────────\t────────────\t────────────\t────────────\t──────────────────\t%s──────────────────%s\n",
+		theme.Gray, extraRule, theme.Reset)
+
+	currentDay := ""
+	for _, h := range v.Hours {
+		// Group rows under a header for each local date
+		if day := h.Time.Format("2006-01-02"); day != currentDay {
+			currentDay = day
+			fmt.Fprintf(w, "%s%s%s\t\t\t\t\t\t%s\n", theme.Bold, h.Time.Format("Monday, 02 Jan 2006"), theme.Reset, strings.Repeat("\t", len(v.vars)))
+		}
+
+		extraCells := ""
+		for _, name := range v.vars {
+			val := varValue(h.Vars, name)
+			extraCells += fmt.Sprintf("%s%6s%s\t", r.valueColor(name, val, un.Variables[name], theme.Blue), formatValue(val), theme.Reset)
 		}
+
+		fmt.Fprintf(w, "%s%-20s%s\t%s%6.1f%s\t%s%6.1f%s\t%s%-4s%s\t%s%6.0f%s\t%s%6s%s\t%s%s%s%s\n",
+			theme.Gray, "  "+h.Time.Format("15:04"), theme.Reset,
+			r.valueColor("temperature", h.Temp, un.Temp, theme.Cyan), h.Temp, theme.Reset,
+			r.valueColor("wind_speed", h.Wind, un.Wind, theme.Yellow), h.Wind, theme.Reset,
+			theme.Yellow, h.Compass, theme.Reset,
+			theme.Blue, h.Humidity, theme.Reset,
+			theme.Cyan, formatPressure(h.Pressure, un.Pressure), theme.Reset,
+			extraCells,
+			theme.Green, description(h.Icon, h.Condition), theme.Reset)
+	}
+	if r.opts.Sparkline && len(v.Hours) > 1 {
+		r.trendRow(w, v)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+	r.writeLegend(out, append([]string{"temperature", "wind_speed"}, v.vars...), un.unitOf)
+	return nil
+}
+
+// sparklineWidth is the width of each cell of the trend row.
+const sparklineWidth = 12
+
+// trendRow writes a sparkline of every numeric hourly column.
+func (r tableRenderer) trendRow(w io.Writer, v *HourlyView) {
+	theme := r.theme
+	spark := func(name string) string {
+		_, _, values := chartSeries(v, name)
+		return chart.Sparkline(values, sparklineWidth, asciiOnly(theme))
+	}
+	extraCells := ""
+	for _, name := range v.vars {
+		extraCells += fmt.Sprintf("%s%s%s\t", theme.Blue, spark(name), theme.Reset)
 	}
+	fmt.Fprintf(w, "%s%-20s%s\t%s%s%s\t%s%s%s\t%s%s\t%s%s%s\t%s%s%s\t%s\n",
+		theme.Gray, "Trend", theme.Reset,
+		theme.Cyan, spark("temperature"), theme.Reset,
+		theme.Yellow, spark("wind_speed"), theme.Reset,
+		theme.Yellow, theme.Reset,
+		theme.Blue, spark("humidity"), theme.Reset,
+		theme.Cyan, spark("pressure"), theme.Reset,
+		extraCells)
+}
 
-	fmt.Printf("\n%sHourly forecast (%s):%s\n", theme.Bold, locName, theme.Reset)
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintf(w, "%s%-20s\t%-12s\t%-12s\t%-12s\t%-12s\t%-16s\t%-16s%s\n",
-		theme.Bold, "Time", "Temp (°C)", "Wind (km/h)", "Dir", "Humidity (%)", "Pressure (hPa)", "Conditions", theme.Reset)
-	fmt.Fprintf(w, "%s──────────────────────\t────────────\t────────────\t────────────\t────────────\t──────────────────\t──────────────────%s\n",
+func (r tableRenderer) Daily(out io.Writer, v *DailyView) error {
+	theme := r.theme
+	un := v.Units
+	fmt.Fprintf(out, "\n%sDaily forecast%s (%s):%s\n", theme.Bold, forLocation(v.Location), v.TimeZone, theme.Reset)
+	w := newTableWriter(out)
+	fmt.Fprintf(w, "%s%-16s\t%-10s\t%-10s\t%-12s\t%-10s\t%-12s\t%-8s\t%-8s\t%-16s%s\n",
+		theme.Bold, "Date", "Max ("+un.Temp+")", "Min ("+un.Temp+")", "Precip ("+un.Precip+")", "Rain (%)", "Wind ("+un.Wind+")", "Sunrise", "Sunset", "Conditions", theme.Reset)
+	fmt.Fprintf(w, "%s────────────────\t──────────\t──────────\t────────────\t──────────\t────────────\t────────\t────────\t──────────────────%s\n",
 		theme.Gray, theme.Reset)
 
-	limit := len(forecast.Hourly.Time)
-	if hours > 0 && hours < limit {
-		limit = hours
+	for _, d := range v.Days {
+		date, _ := time.Parse("2006-01-02", d.Date)
+		fmt.Fprintf(w, "%s%-16s%s\t%s%6.1f%s\t%s%6.1f%s\t%s%6s%s\t%s%6.0f%s\t%s%6.1f%s\t%s%-8s%s\t%s%-8s%s\t%s%s%s\n",
+			theme.Gray, date.Format("Mon 2006-01-02"), theme.Reset,
+			r.valueColor("temperature", d.TempMax, un.Temp, theme.Red), d.TempMax, theme.Reset,
+			r.valueColor("temperature", d.TempMin, un.Temp, theme.Cyan), d.TempMin, theme.Reset,
+			r.valueColor("precipitation", d.Precip, un.Precip, theme.Blue), formatPrecipitation(d.Precip, un.Precip), theme.Reset,
+			theme.Blue, d.PrecipProb, theme.Reset,
+			r.valueColor("wind_speed", d.WindMax, un.Wind, theme.Yellow), d.WindMax, theme.Reset,
+			theme.Yellow, d.Sunrise, theme.Reset,
+			theme.Yellow, d.Sunset, theme.Reset,
+			theme.Green, description(d.Icon, d.Condition), theme.Reset)
 	}
+	w.Flush()
+	fmt.Fprintln(out)
+	r.writeLegend(out, []string{"temperature", "precipitation", "wind_speed"}, un.unitOf)
+	return nil
+}
 
-	for i := 0; i < limit; i++ {
-		tStr := forecast.Hourly.Time[i]
-		var tUTC time.Time
-		var err error
-		if len(tStr) == 16 {
-			tUTC, err = time.Parse("2006-01-02T15:04", tStr)
-		} else {
-			tUTC, err = time.Parse(time.RFC3339, tStr)
+func (r tableRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	if c != nil {
+		current := r
+		if h != nil {
+			// One legend below the hourly table covers both
+			current.opts.Legend = false
 		}
-		if err != nil {
-			log.Logger.Warnw("Failed to parse time", "value", tStr, "error", err)
-			continue
+		if err := current.Current(w, c); err != nil {
+			return err
 		}
-		tLocal := tUTC.In(loc)
-
-		fmt.Fprintf(w, "%s%-20s%s\t%s%6.1f%s\t%s%6.1f%s\t%s%-4s%s\t%s%6.0f%s\t%s%6.0f%s\t%s%s%s\n",
-			theme.Gray, tLocal.Format("2006-01-02 15:04"), theme.Reset,
-			theme.Cyan, forecast.Hourly.Temperature[i], theme.Reset,
-			theme.Yellow, forecast.Hourly.Windspeed[i], theme.Reset,
-			theme.Yellow, degreesToCompass(forecast.Hourly.Winddirection[i]), theme.Reset,
-			theme.Blue, forecast.Hourly.Humidity[i], theme.Reset,
-			theme.Cyan, forecast.Hourly.Pressure[i], theme.Reset,
-			theme.Green, api.WeatherDescription(forecast.Hourly.Weathercode[i]), theme.Reset)
 	}
-	w.Flush()
-	fmt.Println()
+	if h != nil {
+		return r.Hourly(w, h)
+	}
+	return nil
 }
 
 // Utility
@@ -126,3 +231,78 @@ func degreesToCompass(deg float64) string {
 	idx := int((deg+22.5)/45.0) % 8
 	return dirs[idx]
 }
+
+// clockTime extracts HH:MM from an Open-Meteo local timestamp (2006-01-02T15:04).
+func clockTime(ts string) string {
+	if ts == "" {
+		return "-"
+	}
+	t, err := time.Parse("2006-01-02T15:04", ts)
+	if err != nil {
+		return ts
+	}
+	return t.Format("15:04")
+}
+
+// unitLabels fills in metric labels for data cached before units were recorded.
+func unitLabels(u model.Units) model.Units {
+	if u.Temperature == "" {
+		vars := u.Variables
+		u = units.Metric.Labels()
+		u.Variables = vars
+	}
+	return u
+}
+
+// formatPressure uses two decimals for inHg, whole numbers otherwise.
+func formatPressure(v float64, label string) string {
+	if label == units.Label(units.InHg) {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// formatPrecipitation uses two decimals for inches, one otherwise.
+func formatPrecipitation(v float64, label string) string {
+	if label == units.Label(units.Inch) {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// variableNames returns the keys of a variables map in display order.
+func variableNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	api.SortVariables(names)
+	return names
+}
+
+// formatValue prints an optional variable, with "-" for missing values.
+func formatValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// forLocation formats an optional location for a section title.
+func forLocation(location string) string {
+	if location == "" {
+		return ""
+	}
+	return " for " + location
+}
+
+// withUnit appends a unit label unless it is empty.
+func withUnit(s, unit string) string {
+	if unit == "" || unit == "()" {
+		return s
+	}
+	return s + " " + unit
+}
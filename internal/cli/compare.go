@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+	"goweather/internal/model"
+	"goweather/internal/ui"
+)
+
+// CompareResult is the outcome for one location of `goweather compare`.
+type CompareResult struct {
+	Name    string // what the user asked for
+	Label   string // resolved place, empty when geocoding failed
+	Current *model.WeatherResponse
+	Hourly  *model.HourlyForecast
+	Err     error
+}
+
+// CompareView is the view of `goweather compare`: the current conditions of
+// every location, or their hourly forecasts, in the order asked for.
+type CompareView struct {
+	Locations []CompareLocation `json:"locations" yaml:"locations"`
+}
+
+// CompareLocation is one location of a CompareView. Error is set and the
+// views are nil when it could not be fetched.
+type CompareLocation struct {
+	Name    string       `json:"name" yaml:"name"`
+	Current *CurrentView `json:"current,omitempty" yaml:"current,omitempty"`
+	Hourly  *HourlyView  `json:"hourly,omitempty" yaml:"hourly,omitempty"`
+	Error   string       `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// NewCompareView builds the view of compare results, keeping the first
+// hours of hourly forecasts (all of them when hours <= 0).
+func NewCompareView(results []CompareResult, hours int, cfg *config.Config) *CompareView {
+	v := &CompareView{Locations: []CompareLocation{}}
+	for _, r := range results {
+		l := CompareLocation{Name: r.Name}
+		switch {
+		case r.Err != nil:
+			l.Error = r.Err.Error()
+		case r.Hourly != nil:
+			l.Hourly = NewHourlyView(r.Hourly, hours, cfg)
+		case r.Current != nil:
+			l.Current = NewCurrentView(r.Current, cfg)
+		}
+		v.Locations = append(v.Locations, l)
+	}
+	return v
+}
+
+// fetched drops the locations that failed.
+func (v *CompareView) fetched() []CompareLocation {
+	var ok []CompareLocation
+	for _, l := range v.Locations {
+		if l.Current != nil || l.Hourly != nil {
+			ok = append(ok, l)
+		}
+	}
+	return ok
+}
+
+// title is the column header of a location: the resolved place, else the
+// name asked for.
+func (l CompareLocation) title() string {
+	switch {
+	case l.Current != nil && l.Current.Location != "":
+		return l.Current.Location
+	case l.Hourly != nil && l.Hourly.Location != "":
+		return l.Hourly.Location
+	}
+	return l.Name
+}
+
+// Compare renders current conditions side by side, one column per location,
+// or with --hourly a grid with a row per hour showing temperature and icon.
+func (r tableRenderer) Compare(out io.Writer, v *CompareView) error {
+	ok := v.fetched()
+	if len(ok) == 0 {
+		return nil
+	}
+	if ok[0].Hourly != nil {
+		return r.compareHourly(out, ok)
+	}
+	theme := r.theme
+	un := ok[0].Current.Units
+	fmt.Fprintf(out, "\n%sCurrent weather:%s\n", theme.Bold, theme.Reset)
+	w := newTableWriter(out)
+
+	row := func(color, name string, cell func(*CurrentView) string) {
+		fmt.Fprintf(w, "%s%s%s", color, name, theme.Reset)
+		for _, l := range ok {
+			fmt.Fprintf(w, "\t%s", cell(l.Current))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%s%-16s", theme.Bold, "Parameter")
+	for _, l := range ok {
+		fmt.Fprintf(w, "\t%s", shortName(l.title()))
+	}
+	fmt.Fprintf(w, "%s\n", theme.Reset)
+	fmt.Fprintf(w, "%s────────────────%s%s\n", theme.Gray, strings.Repeat("\t────────────", len(ok)), theme.Reset)
+
+	row(theme.Cyan, "Temperature", func(c *CurrentView) string {
+		return fmt.Sprintf("%.1f %s", c.Temp, un.Temp)
+	})
+	row(theme.Blue, "Humidity", func(c *CurrentView) string {
+		return fmt.Sprintf("%.0f %%", c.Humidity)
+	})
+	row(theme.Yellow, "Wind", func(c *CurrentView) string {
+		return fmt.Sprintf("%.1f %s %s", c.Wind, un.Wind, c.Compass)
+	})
+	row(theme.Green, "Pressure", func(c *CurrentView) string {
+		return formatPressure(c.Pressure, un.Pressure) + " " + un.Pressure
+	})
+	for _, name := range compareVars(ok) {
+		row(theme.Blue, api.LookupVariable(name).Label, func(c *CurrentView) string {
+			return withUnit(formatValue(varValue(c.Vars, name)), c.Units.Variables[name])
+		})
+	}
+	row(theme.Red, "Condition", func(c *CurrentView) string {
+		return description(c.Icon, c.Condition)
+	})
+	w.Flush()
+	_, err := fmt.Fprintln(out)
+	return err
+}
+
+// compareVars lists the extra variables of any location, in order.
+func compareVars(ok []CompareLocation) []string {
+	var vars []string
+	for _, l := range ok {
+		for _, name := range l.Current.vars {
+			if !slices.Contains(vars, name) {
+				vars = append(vars, name)
+			}
+		}
+	}
+	return vars
+}
+
+// compareHourly renders the hourly grid of Compare. The first location sets
+// the rows; the others are matched by instant.
+func (r tableRenderer) compareHourly(out io.Writer, ok []CompareLocation) error {
+	theme := r.theme
+	first := ok[0].Hourly
+	fmt.Fprintf(out, "\n%sHourly temperature (%s, %s):%s\n", theme.Bold, first.Units.Temp, first.TimeZone, theme.Reset)
+	w := newTableWriter(out)
+
+	fmt.Fprintf(w, "%s%-20s", theme.Bold, "Time")
+	for _, l := range ok {
+		fmt.Fprintf(w, "\t%s", shortName(l.title()))
+	}
+	fmt.Fprintf(w, "%s\n", theme.Reset)
+	fmt.Fprintf(w, "%s────────────────────%s%s\n", theme.Gray, strings.Repeat("\t────────────", len(ok)), theme.Reset)
+
+	byTime := make([]map[time.Time]Conditions, len(ok))
+	for i, l := range ok {
+		byTime[i] = make(map[time.Time]Conditions)
+		for _, h := range l.Hourly.Hours {
+			byTime[i][h.Time.UTC()] = h
+		}
+	}
+
+	currentDay := ""
+	for _, hour := range first.Hours {
+		if day := hour.Time.Format("2006-01-02"); day != currentDay {
+			currentDay = day
+			fmt.Fprintf(w, "%s%s%s%s\n", theme.Bold, hour.Time.Format("Monday, 02 Jan 2006"), theme.Reset, strings.Repeat("\t", len(ok)))
+		}
+		fmt.Fprintf(w, "%s%-20s%s", theme.Gray, "  "+hour.Time.Format("15:04"), theme.Reset)
+		for i := range ok {
+			h, found := byTime[i][hour.Time.UTC()]
+			if !found {
+				fmt.Fprintf(w, "\t%s-%s", theme.Gray, theme.Reset)
+				continue
+			}
+			fmt.Fprintf(w, "\t%s%6.1f%s %s", theme.Cyan, h.Temp, theme.Reset, h.Icon)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+	_, err := fmt.Fprintln(out)
+	return err
+}
+
+// PrintCompareFailures lists the locations that could not be fetched on stderr.
+func PrintCompareFailures(results []CompareResult, theme ui.Theme) {
+	var failed []CompareResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s⚠ Could not fetch %d of %d locations:%s\n", theme.Yellow, len(failed), len(results), theme.Reset)
+	for _, r := range failed {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", r.Name, r.Err)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// displayLocation returns the configured display time zone and its name.
+func displayLocation(cfg *config.Config) (*time.Location, string) {
+	if cfg != nil && cfg.TimeZone != "" && cfg.TimeZone != "local" {
+		if userLoc, err := time.LoadLocation(cfg.TimeZone); err == nil {
+			return userLoc, cfg.TimeZone
+		}
+	}
+	return time.Local, "Local"
+}
+
+// ParseHour parses provider hourly timestamps (Open-Meteo's 2006-01-02T15:04
+// in UTC, or RFC 3339).
+func ParseHour(ts string) (time.Time, error) {
+	if len(ts) == 16 {
+		return time.Parse("2006-01-02T15:04", ts)
+	}
+	return time.Parse(time.RFC3339, ts)
+}
+
+// shortName keeps column headers narrow: the part of a label before the first comma.
+func shortName(label string) string {
+	if i := strings.Index(label, ","); i > 0 {
+		return label[:i]
+	}
+	return label
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/cache"
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// BothFetcher fetches current conditions and the hourly forecast of one
+// location through the cache.
+type BothFetcher struct {
+	Provider api.Provider
+	Units    units.Units
+	Vars     []string
+	Coords   *api.Coordinates
+	Cache    *cache.Cache
+	Key      string // location part of the cache keys
+	Hours    int
+}
+
+// Keys returns the cache keys of the current and hourly data.
+func (f *BothFetcher) Keys() (current, hourly string) {
+	varsKey := api.VariablesKey(f.Vars)
+	current = fmt.Sprintf("%s_current_%s_%s", f.Key, f.Units.Key(), varsKey)
+	hourly = fmt.Sprintf("%s_hourly_%d_%s_%s", f.Key, api.DaysForHours(f.Hours), f.Units.Key(), varsKey)
+	return current, hourly
+}
+
+func (f *BothFetcher) fetchCurrent(ctx context.Context) (any, error) {
+	data, err := f.Provider.Current(ctx, f.Coords.Latitude, f.Coords.Longitude, f.Units, f.Vars)
+	if err != nil {
+		return nil, err
+	}
+	data.Location = f.Coords.Label()
+	return data, nil
+}
+
+func (f *BothFetcher) fetchHourly(ctx context.Context) (any, error) {
+	data, err := f.Provider.Hourly(ctx, f.Coords.Latitude, f.Coords.Longitude, api.DaysForHours(f.Hours), f.Units, f.Vars)
+	if err != nil {
+		return nil, err
+	}
+	data.Location = f.Coords.Label()
+	return data, nil
+}
+
+// Fetch returns both halves, taking fresh ones from the cache and fetching
+// the rest in parallel under a shared timeout (none when timeout <= 0).
+func (f *BothFetcher) Fetch(ctx context.Context, timeout time.Duration) (current *model.WeatherResponse, hourly *model.HourlyForecast, curErr, hrsErr error) {
+	return f.fetch(ctx, timeout, true)
+}
+
+// Refresh is Fetch without reading the cache: both halves are fetched and
+// stored, however recently they were cached.
+func (f *BothFetcher) Refresh(ctx context.Context, timeout time.Duration) (current *model.WeatherResponse, hourly *model.HourlyForecast, curErr, hrsErr error) {
+	return f.fetch(ctx, timeout, false)
+}
+
+func (f *BothFetcher) fetch(ctx context.Context, timeout time.Duration, useCache bool) (current *model.WeatherResponse, hourly *model.HourlyForecast, curErr, hrsErr error) {
+	curKey, hrsKey := f.Keys()
+	var currentData, hourlyData any
+	if useCache {
+		currentData, _ = f.Cache.Get(curKey)
+		hourlyData, _ = f.Cache.Get(hrsKey)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var wg sync.WaitGroup
+	if currentData == nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if currentData, curErr = f.fetchCurrent(ctx); curErr == nil {
+				f.Cache.Set(curKey, currentData)
+			}
+		}()
+	}
+	if hourlyData == nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if hourlyData, hrsErr = f.fetchHourly(ctx); hrsErr == nil {
+				f.Cache.Set(hrsKey, hourlyData)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if curErr == nil {
+		current = currentData.(*model.WeatherResponse)
+	}
+	if hrsErr == nil {
+		hourly = hourlyData.(*model.HourlyForecast)
+	}
+	return current, hourly, curErr, hrsErr
+}
+
+// Updated returns when the oldest of the two cached halves was stored.
+func (f *BothFetcher) Updated() (time.Time, bool) {
+	curKey, hrsKey := f.Keys()
+	var oldest time.Time
+	found := false
+	for _, key := range []string{curKey, hrsKey} {
+		if t, ok := f.Cache.Updated(key); ok && (!found || t.Before(oldest)) {
+			oldest, found = t, true
+		}
+	}
+	return oldest, found
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+	"goweather/internal/ui"
+)
+
+// PrintLocations lists geocoding candidates as a numbered table.
+func PrintLocations(w io.Writer, candidates []api.Coordinates, theme ui.Theme) {
+	tw := newTableWriter(w)
+	fmt.Fprintf(tw, "%s#\tName\tRegion\tCountry\tPopulation\tLatitude\tLongitude%s\n", theme.Bold, theme.Reset)
+	for i, c := range candidates {
+		fmt.Fprintf(tw, "%s%d%s\t%s\t%s\t%s\t%s\t%.4f\t%.4f\n",
+			theme.Gray, i+1, theme.Reset,
+			c.Name, orDash(c.Admin1), countryLabel(c), population(c.Population), c.Latitude, c.Longitude)
+	}
+	tw.Flush()
+}
+
+// PickLocation shows the candidates on stderr and reads a 1-based choice from
+// stdin. An empty answer picks the first candidate.
+func PickLocation(candidates []api.Coordinates, theme ui.Theme) (int, error) {
+	fmt.Fprintf(os.Stderr, "\n%sSeveral places match \"%s\":%s\n", theme.Bold, candidates[0].Name, theme.Reset)
+	PrintLocations(os.Stderr, candidates, theme)
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "Choose a location [1-%d] (default 1): ", len(candidates))
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil {
+				return 0, fmt.Errorf("no location selected: %w", err)
+			}
+			return 0, nil
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= len(candidates) {
+			return n - 1, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("no location selected: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Please enter a number between 1 and %d.\n", len(candidates))
+	}
+}
+
+// PrintGeocodeCache lists remembered locations with their age.
+func PrintGeocodeCache(w io.Writer, entries []api.GeocodeCacheEntry, ttl time.Duration, theme ui.Theme) {
+	tw := newTableWriter(w)
+	fmt.Fprintf(tw, "%sKey\tLocation\tLatitude\tLongitude\tCached%s\n", theme.Bold, theme.Reset)
+	for _, e := range entries {
+		age := cacheAge(e.CachedAt)
+		if e.Expired(ttl) {
+			age = theme.Gray + age + " (expired)" + theme.Reset
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.4f\t%.4f\t%s\n", e.Key, e.Label(), e.Latitude, e.Longitude, age)
+	}
+	tw.Flush()
+}
+
+// PrintGeocodeCacheEntry shows every detail of one remembered location.
+func PrintGeocodeCacheEntry(w io.Writer, e api.GeocodeCacheEntry, ttl time.Duration) {
+	tw := newTableWriter(w)
+	fmt.Fprintf(tw, "Key\t%s\n", e.Key)
+	fmt.Fprintf(tw, "Name\t%s\n", e.Name)
+	fmt.Fprintf(tw, "Region\t%s\n", orDash(e.Admin1))
+	fmt.Fprintf(tw, "Country\t%s\n", countryLabel(e.Coordinates))
+	fmt.Fprintf(tw, "Population\t%s\n", population(e.Population))
+	fmt.Fprintf(tw, "Coordinates\t%.4f, %.4f\n", e.Latitude, e.Longitude)
+	fmt.Fprintf(tw, "Timezone\t%s\n", orDash(e.Timezone))
+	fmt.Fprintf(tw, "Cached\t%s\n", cacheAge(e.CachedAt))
+	switch {
+	case ttl == 0:
+		fmt.Fprintf(tw, "Expires\tnever\n")
+	case e.Expired(ttl):
+		fmt.Fprintf(tw, "Expires\texpired\n")
+	default:
+		fmt.Fprintf(tw, "Expires\t%s\n", e.CachedAt.Add(ttl).Local().Format("2006-01-02 15:04"))
+	}
+	tw.Flush()
+}
+
+// cacheAge formats when an entry was cached; older entries have no timestamp.
+func cacheAge(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// PrintSavedLocations lists the locations saved in the config.
+func PrintSavedLocations(w io.Writer, locations config.Locations, theme ui.Theme) {
+	tw := newTableWriter(w)
+	fmt.Fprintf(tw, "%sName\tLabel\tPlace\tTime zone\tUnits%s\n", theme.Bold, theme.Reset)
+	for _, name := range locations.Names() {
+		l := locations[name]
+		place := l.City
+		if l.HasCoordinates() {
+			place = fmt.Sprintf("%.4f, %.4f", *l.Latitude, *l.Longitude)
+		}
+		for _, filter := range []string{l.Admin1, l.Country} {
+			if filter != "" {
+				place += ", " + filter
+			}
+		}
+		fmt.Fprintf(tw, "%s%s%s\t%s\t%s\t%s\t%s\n",
+			theme.Cyan, name, theme.Reset, orDash(l.Label), orDash(place), orDash(l.TimeZone), orDash(savedUnits(l.Units)))
+	}
+	tw.Flush()
+}
+
+// savedUnits summarizes a location's unit settings, e.g. "imperial, wind kn".
+func savedUnits(u config.UnitsConfig) string {
+	var parts []string
+	if u.System != "" {
+		parts = append(parts, u.System)
+	}
+	for _, o := range []struct{ name, value string }{
+		{"temp", u.Temperature}, {"wind", u.WindSpeed}, {"precip", u.Precipitation}, {"pressure", u.Pressure},
+	} {
+		if o.value != "" {
+			parts = append(parts, o.name+" "+o.value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func countryLabel(c api.Coordinates) string {
+	if c.CountryCode == "" {
+		return orDash(c.Country)
+	}
+	return fmt.Sprintf("%s (%s)", c.Country, c.CountryCode)
+}
+
+func population(n int) string {
+	if n <= 0 {
+		return "-"
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"goweather/internal/api"
+)
+
+// column is one column of the CSV and Markdown renderers. raw is the
+// machine-readable cell, pretty the one shown to people.
+type column[T any] struct {
+	name   string // CSV header
+	label  string // Markdown header
+	raw    func(T) string
+	pretty func(T) string
+}
+
+// conditionColumns are the columns of current and hourly records.
+func conditionColumns(m Meta, vars []string) []column[Conditions] {
+	un := m.Units
+	cols := []column[Conditions]{
+		{"time", "Time",
+			func(c Conditions) string { return c.Time.Format(time.RFC3339) },
+			func(c Conditions) string { return c.Time.Format("Mon 02 Jan 15:04") }},
+		{"temperature", "Temp (" + un.Temp + ")",
+			func(c Conditions) string { return rawNumber(c.Temp) },
+			func(c Conditions) string { return fmt.Sprintf("%.1f", c.Temp) }},
+		{"humidity", "Humidity (%)",
+			func(c Conditions) string { return rawNumber(c.Humidity) },
+			func(c Conditions) string { return fmt.Sprintf("%.0f", c.Humidity) }},
+		{"wind_speed", "Wind (" + un.Wind + ")",
+			func(c Conditions) string { return rawNumber(c.Wind) },
+			func(c Conditions) string { return fmt.Sprintf("%.1f", c.Wind) }},
+		{"wind_direction", "Dir",
+			func(c Conditions) string { return rawNumber(c.WindDir) },
+			func(c Conditions) string { return c.Compass }},
+		{"pressure", "Pressure (" + un.Pressure + ")",
+			func(c Conditions) string { return rawNumber(c.Pressure) },
+			func(c Conditions) string { return formatPressure(c.Pressure, un.Pressure) }},
+	}
+	for _, name := range vars {
+		cols = append(cols, column[Conditions]{name, withUnit(api.LookupVariable(name).Label, "("+un.Variables[name]+")"),
+			func(c Conditions) string { return rawNumber(varValue(c.Vars, name)) },
+			func(c Conditions) string { return formatValue(varValue(c.Vars, name)) }})
+	}
+	return append(cols,
+		column[Conditions]{"weather_code", "Code",
+			func(c Conditions) string { return strconv.Itoa(c.Code) },
+			func(c Conditions) string { return strconv.Itoa(c.Code) }},
+		column[Conditions]{"condition", "Conditions",
+			func(c Conditions) string { return c.Condition },
+			func(c Conditions) string { return description(c.Icon, c.Condition) }},
+	)
+}
+
+// dayColumns are the columns of daily records.
+func dayColumns(m Meta) []column[Day] {
+	un := m.Units
+	return []column[Day]{
+		{"date", "Date",
+			func(d Day) string { return d.Date },
+			func(d Day) string { return d.Date }},
+		{"temperature_max", "Max (" + un.Temp + ")",
+			func(d Day) string { return rawNumber(d.TempMax) },
+			func(d Day) string { return fmt.Sprintf("%.1f", d.TempMax) }},
+		{"temperature_min", "Min (" + un.Temp + ")",
+			func(d Day) string { return rawNumber(d.TempMin) },
+			func(d Day) string { return fmt.Sprintf("%.1f", d.TempMin) }},
+		{"precipitation", "Precip (" + un.Precip + ")",
+			func(d Day) string { return rawNumber(d.Precip) },
+			func(d Day) string { return formatPrecipitation(d.Precip, un.Precip) }},
+		{"precipitation_probability", "Rain (%)",
+			func(d Day) string { return rawNumber(d.PrecipProb) },
+			func(d Day) string { return fmt.Sprintf("%.0f", d.PrecipProb) }},
+		{"wind_speed_max", "Wind (" + un.Wind + ")",
+			func(d Day) string { return rawNumber(d.WindMax) },
+			func(d Day) string { return fmt.Sprintf("%.1f", d.WindMax) }},
+		{"sunrise", "Sunrise",
+			func(d Day) string { return d.Sunrise },
+			func(d Day) string { return d.Sunrise }},
+		{"sunset", "Sunset",
+			func(d Day) string { return d.Sunset },
+			func(d Day) string { return d.Sunset }},
+		{"weather_code", "Code",
+			func(d Day) string { return strconv.Itoa(d.Code) },
+			func(d Day) string { return strconv.Itoa(d.Code) }},
+		{"condition", "Conditions",
+			func(d Day) string { return d.Condition },
+			func(d Day) string { return description(d.Icon, d.Condition) }},
+	}
+}
+
+// varValue looks up an optional variable, NaN when missing.
+func varValue(vars map[string]float64, name string) float64 {
+	if v, ok := vars[name]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+// rawNumber formats v with as many digits as needed, empty when missing.
+func rawNumber(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// csvRenderer writes a header row and a record per instant or day, with the
+// location in the first column. Current and hourly records start with a kind
+// column so the two can be told apart when mixed.
+type csvRenderer struct{}
+
+func (r csvRenderer) Current(w io.Writer, v *CurrentView) error {
+	return r.Both(w, v, nil)
+}
+
+func (r csvRenderer) Hourly(w io.Writer, v *HourlyView) error {
+	return r.Both(w, nil, v)
+}
+
+func (csvRenderer) Daily(w io.Writer, v *DailyView) error {
+	return writeCSV(w, []string{"location"}, func(int) []string { return []string{v.Location} }, dayColumns(v.Meta), v.Days)
+}
+
+// Both writes one table: the current conditions first, then every hour.
+func (csvRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	var t conditionTable
+	t.add(c, h)
+	return t.write(w)
+}
+
+// Compare writes one table with the rows of every location that could be fetched.
+func (csvRenderer) Compare(w io.Writer, v *CompareView) error {
+	var t conditionTable
+	for _, l := range v.fetched() {
+		t.add(l.Current, l.Hourly)
+	}
+	return t.write(w)
+}
+
+// conditionTable collects the current and hourly records of a CSV, with the
+// kind and location of each and the extra variables of any of them.
+type conditionTable struct {
+	meta  Meta
+	vars  []string
+	rows  []Conditions
+	kinds []string
+	where []string
+}
+
+// add appends current conditions and hourly forecast rows (either may be nil).
+func (t *conditionTable) add(c *CurrentView, h *HourlyView) {
+	if c != nil {
+		t.meta = c.Meta
+		t.addVars(c.vars)
+		t.rows = append(t.rows, c.Conditions)
+		t.kinds = append(t.kinds, "current")
+		t.where = append(t.where, c.Location)
+	}
+	if h != nil {
+		t.meta = h.Meta
+		t.addVars(h.vars)
+		for _, row := range h.Hours {
+			t.rows = append(t.rows, row)
+			t.kinds = append(t.kinds, "hourly")
+			t.where = append(t.where, h.Location)
+		}
+	}
+}
+
+// addVars adds the variables not seen yet, keeping their order.
+func (t *conditionTable) addVars(vars []string) {
+	for _, name := range vars {
+		if !slices.Contains(t.vars, name) {
+			t.vars = append(t.vars, name)
+		}
+	}
+}
+
+func (t *conditionTable) write(w io.Writer) error {
+	lead := func(i int) []string { return []string{t.kinds[i], t.where[i]} }
+	return writeCSV(w, []string{"kind", "location"}, lead, conditionColumns(t.meta, t.vars), t.rows)
+}
+
+// writeCSV writes a header and a record per row. The lead columns, named in
+// header, come first with the cells returned for row i.
+func writeCSV[T any](w io.Writer, header []string, lead func(i int) []string, cols []column[T], rows []T) error {
+	cw := csv.NewWriter(w)
+	for _, col := range cols {
+		header = append(header, col.name)
+	}
+	cw.Write(header)
+	for i, row := range rows {
+		record := lead(i)
+		for _, col := range cols {
+			record = append(record, col.raw(row))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// markdownRenderer writes a heading and a GitHub-flavored table per view.
+type markdownRenderer struct{}
+
+func (markdownRenderer) Current(w io.Writer, v *CurrentView) error {
+	return writeMarkdown(w, "Current weather"+forLocation(v.Location), conditionColumns(v.Meta, v.vars), []Conditions{v.Conditions})
+}
+
+func (markdownRenderer) Hourly(w io.Writer, v *HourlyView) error {
+	title := fmt.Sprintf("Hourly forecast%s (%s)", forLocation(v.Location), v.TimeZone)
+	return writeMarkdown(w, title, conditionColumns(v.Meta, v.vars), v.Hours)
+}
+
+func (markdownRenderer) Daily(w io.Writer, v *DailyView) error {
+	title := fmt.Sprintf("Daily forecast%s (%s)", forLocation(v.Location), v.TimeZone)
+	return writeMarkdown(w, title, dayColumns(v.Meta), v.Days)
+}
+
+func (r markdownRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	if c != nil {
+		if err := r.Current(w, c); err != nil {
+			return err
+		}
+	}
+	if h != nil {
+		if c != nil {
+			fmt.Fprintln(w)
+		}
+		return r.Hourly(w, h)
+	}
+	return nil
+}
+
+func writeMarkdown[T any](w io.Writer, title string, cols []column[T], rows []T) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n|", markdownEscape(title))
+	for _, col := range cols {
+		fmt.Fprintf(&b, " %s |", markdownEscape(col.label))
+	}
+	b.WriteString("\n|")
+	for range cols {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		b.WriteString("|")
+		for _, col := range cols {
+			fmt.Fprintf(&b, " %s |", markdownEscape(col.pretty(row)))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Compare writes a section per location that could be fetched.
+func (r markdownRenderer) Compare(w io.Writer, v *CompareView) error {
+	for i, l := range v.fetched() {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := r.Both(w, l.Current, l.Hourly); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownEscape keeps cell text from breaking the table.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
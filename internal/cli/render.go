@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"goweather/internal/ui"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatYAML     = "yaml"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the output formats in the order shown in help texts.
+var Formats = []string{FormatTable, FormatJSON, FormatNDJSON, FormatYAML, FormatCSV, FormatMarkdown}
+
+// Renderer writes views in one output format.
+type Renderer interface {
+	Current(w io.Writer, v *CurrentView) error
+	Hourly(w io.Writer, v *HourlyView) error
+	Daily(w io.Writer, v *DailyView) error
+	// Both writes current conditions and the hourly forecast as one
+	// document. Either may be nil when it could not be fetched.
+	Both(w io.Writer, current *CurrentView, hourly *HourlyView) error
+	// Compare writes several locations side by side. Locations that could
+	// not be fetched only carry an error.
+	Compare(w io.Writer, v *CompareView) error
+}
+
+// NewRenderer returns the renderer for an output format. The theme only
+// affects the table format.
+func NewRenderer(format string, theme ui.Theme) (Renderer, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatTable:
+		return tableRenderer{theme: theme}, nil
+	case FormatJSON:
+		return jsonRenderer{}, nil
+	case FormatNDJSON:
+		return ndjsonRenderer{}, nil
+	case FormatYAML, "yml":
+		return yamlRenderer{}, nil
+	case FormatCSV:
+		return csvRenderer{}, nil
+	case FormatMarkdown, "md":
+		return markdownRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// bothView is the single document written by the JSON and YAML renderers
+// for `goweather both`.
+type bothView struct {
+	Current *CurrentView `json:"current,omitempty" yaml:"current,omitempty"`
+	Hourly  *HourlyView  `json:"hourly,omitempty" yaml:"hourly,omitempty"`
+}
+
+// jsonRenderer writes each view as one indented JSON document.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Current(w io.Writer, v *CurrentView) error { return writeJSON(w, v) }
+func (jsonRenderer) Hourly(w io.Writer, v *HourlyView) error   { return writeJSON(w, v) }
+func (jsonRenderer) Daily(w io.Writer, v *DailyView) error     { return writeJSON(w, v) }
+func (jsonRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	return writeJSON(w, bothView{Current: c, Hourly: h})
+}
+func (jsonRenderer) Compare(w io.Writer, v *CompareView) error { return writeJSON(w, v) }
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ndjsonRenderer writes one compact JSON object per line: a line for
+// current conditions and a line per hour or day, each carrying the location
+// and units so lines can be processed on their own.
+type ndjsonRenderer struct{}
+
+func (ndjsonRenderer) Current(w io.Writer, v *CurrentView) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func (ndjsonRenderer) Hourly(w io.Writer, v *HourlyView) error {
+	enc := json.NewEncoder(w)
+	for _, h := range v.Hours {
+		if err := enc.Encode(CurrentView{Meta: v.Meta, Conditions: h}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ndjsonRenderer) Daily(w io.Writer, v *DailyView) error {
+	enc := json.NewEncoder(w)
+	for _, d := range v.Days {
+		line := struct {
+			Meta
+			Day
+		}{v.Meta, d}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r ndjsonRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	if c != nil {
+		if err := r.Current(w, c); err != nil {
+			return err
+		}
+	}
+	if h != nil {
+		return r.Hourly(w, h)
+	}
+	return nil
+}
+
+// Compare writes the lines of every location that could be fetched.
+func (r ndjsonRenderer) Compare(w io.Writer, v *CompareView) error {
+	for _, l := range v.fetched() {
+		if err := r.Both(w, l.Current, l.Hourly); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlRenderer writes each view as one YAML document.
+type yamlRenderer struct{}
+
+func (yamlRenderer) Current(w io.Writer, v *CurrentView) error { return writeYAML(w, v) }
+func (yamlRenderer) Hourly(w io.Writer, v *HourlyView) error   { return writeYAML(w, v) }
+func (yamlRenderer) Daily(w io.Writer, v *DailyView) error     { return writeYAML(w, v) }
+func (yamlRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	return writeYAML(w, bothView{Current: c, Hourly: h})
+}
+func (yamlRenderer) Compare(w io.Writer, v *CompareView) error { return writeYAML(w, v) }
+
+func writeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/ui"
+	"goweather/internal/units"
+)
+
+// TableOptions configures the table renderer.
+type TableOptions struct {
+	Sparkline bool          // add a trend row below hourly tables
+	Scales    ui.Scales     // color values by magnitude; nil keeps the theme's colors
+	Depth     ui.ColorDepth // colors the terminal can show
+	Legend    bool          // describe the scales below each table
+}
+
+// scaleFor returns the color scale of a column or variable and its name:
+// the scale called name, else the one of the quantity it measures, so
+// apparent_temperature uses the temperature scale.
+func (r tableRenderer) scaleFor(name string) (string, ui.Scale, bool) {
+	if s, ok := r.opts.Scales[name]; ok {
+		return name, s, true
+	}
+	switch api.LookupVariable(name).Quantity {
+	case units.QuantityTemperature:
+		name = "temperature"
+	case units.QuantityWindSpeed:
+		name = "wind_speed"
+	case units.QuantityPrecipitation:
+		name = "precipitation"
+	default:
+		return "", ui.Scale{}, false
+	}
+	s, ok := r.opts.Scales[name]
+	return name, s, ok
+}
+
+// valueColor returns the color of v, shown in unit, on the scale of name,
+// or fallback when there is no such scale.
+func (r tableRenderer) valueColor(name string, v float64, unit, fallback string) string {
+	_, s, ok := r.scaleFor(name)
+	if !ok {
+		return fallback
+	}
+	if s.Unit != "" {
+		converted, err := units.Convert(v, unit, s.Unit)
+		if err != nil {
+			return fallback
+		}
+		v = converted
+	}
+	if c := s.Color(v, r.theme, r.opts.Depth); c != "" {
+		return c
+	}
+	return fallback
+}
+
+// scaleLabels name the built-in columns in legends.
+var scaleLabels = map[string]string{
+	"temperature":   "Temperature",
+	"wind_speed":    "Wind",
+	"precipitation": "Precipitation",
+}
+
+// writeLegend describes the scales used by the columns in names, whose
+// values are shown in the units unitOf returns, with thresholds in those units.
+func (r tableRenderer) writeLegend(w io.Writer, names []string, unitOf func(name string) string) {
+	theme := r.theme
+	if !r.opts.Legend || theme.Reset == "" {
+		return
+	}
+	block := "██"
+	if asciiOnly(theme) {
+		block = "##"
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		scaleName, s, ok := r.scaleFor(name)
+		if !ok || seen[scaleName] {
+			continue
+		}
+		seen[scaleName] = true
+		unit := unitOf(name)
+		label, ok := scaleLabels[scaleName]
+		if !ok {
+			label = api.LookupVariable(scaleName).Label
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s%s%s", theme.Gray, withUnit(label, "("+unit+")"), theme.Reset)
+		for _, stop := range s.Stops {
+			from := stop.From
+			if s.Unit != "" {
+				if converted, err := units.Convert(from, s.Unit, unit); err == nil {
+					from = converted
+				}
+			}
+			fmt.Fprintf(&b, "  %s%s%s %s", s.Color(stop.From, theme, r.opts.Depth), block, theme.Reset,
+				strconv.FormatFloat(math.Round(from*10)/10, 'f', -1, 64))
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
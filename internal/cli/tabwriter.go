@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// ansiEscape matches the color sequences themes put into table cells.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+// padMark stands in for padding while aligning; cells never contain it.
+const padMark = '\x00'
+
+// tableWriter aligns tab-separated cells like text/tabwriter but ignores
+// ANSI escape sequences when measuring them, so columns line up whatever
+// the length of a theme's color codes.
+type tableWriter struct {
+	out io.Writer
+	buf bytes.Buffer
+}
+
+// newTableWriter returns a tableWriter with the padding of the terminal tables.
+func newTableWriter(out io.Writer) *tableWriter {
+	return &tableWriter{out: out}
+}
+
+func (t *tableWriter) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Flush aligns and writes everything written so far.
+func (t *tableWriter) Flush() error {
+	lines := strings.Split(t.buf.String(), "\n")
+	t.buf.Reset()
+
+	// Align the text without escapes, padding with padMark so the padding
+	// after each cell can be found again.
+	var plain bytes.Buffer
+	tw := tabwriter.NewWriter(&plain, 0, 0, 3, padMark, 0)
+	for i, line := range lines {
+		if i > 0 {
+			io.WriteString(tw, "\n")
+		}
+		io.WriteString(tw, ansiEscape.ReplaceAllString(line, ""))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Put the original cells, escapes included, in front of that padding.
+	var out strings.Builder
+	for i, aligned := range strings.Split(plain.String(), "\n") {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		if i >= len(lines) {
+			continue
+		}
+		pos := 0
+		for _, cell := range strings.Split(lines[i], "\t") {
+			pos += len(ansiEscape.ReplaceAllString(cell, ""))
+			pad := 0
+			for pos+pad < len(aligned) && aligned[pos+pad] == padMark {
+				pad++
+			}
+			out.WriteString(cell)
+			out.WriteString(strings.Repeat(" ", pad))
+			pos += pad
+		}
+	}
+	_, err := io.WriteString(t.out, out.String())
+	return err
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/template"
+
+	"goweather/internal/api"
+	"goweather/internal/units"
+)
+
+// TemplateFuncs are the helpers available to --format templates.
+var TemplateFuncs = template.FuncMap{
+	// round rounds to the nearest whole number
+	"round": func(v float64) float64 { return math.Round(v) },
+	// fixed formats with a fixed number of decimals: {{.Temp | fixed 1}}
+	"fixed": func(decimals int, v float64) string { return fmt.Sprintf("%.*f", decimals, v) },
+	// compass turns degrees into N, NE, E, ...
+	"compass": degreesToCompass,
+	// icon (the emoji) and condition (the default text) describe a WMO
+	// weather code; .Icon and .Condition honor the icon set and overrides
+	"icon":      api.WeatherIcon,
+	"condition": func(code int) string { return api.ConditionFromWMO(code).String() },
+	// convert changes units: {{.Temp | convert .Units.Temp "fahrenheit"}}
+	"convert": func(from, to string, v float64) (float64, error) { return units.Convert(v, from, to) },
+}
+
+// templateRenderer executes a user template with the view as its data. A
+// newline is added unless the output already ends with one.
+type templateRenderer struct {
+	tmpl *template.Template
+}
+
+// NewTemplateRenderer parses a --format template.
+func NewTemplateRenderer(text string) (Renderer, error) {
+	tmpl, err := template.New("format").Funcs(TemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return templateRenderer{tmpl: tmpl}, nil
+}
+
+func (r templateRenderer) Current(w io.Writer, v *CurrentView) error { return r.execute(w, v) }
+func (r templateRenderer) Hourly(w io.Writer, v *HourlyView) error   { return r.execute(w, v) }
+func (r templateRenderer) Daily(w io.Writer, v *DailyView) error     { return r.execute(w, v) }
+
+// Both executes the template once with .Current and .Hourly set; either
+// is nil when it could not be fetched.
+func (r templateRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	return r.execute(w, bothView{Current: c, Hourly: h})
+}
+
+// Compare executes the template once with .Locations set.
+func (r templateRenderer) Compare(w io.Writer, v *CompareView) error { return r.execute(w, v) }
+
+func (r templateRenderer) execute(w io.Writer, data any) error {
+	var b strings.Builder
+	if err := r.tmpl.Execute(&b, data); err != nil {
+		return err
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// waybarRenderer writes the JSON object Waybar's custom modules read:
+// a short text, a multi-line tooltip and a CSS class named after the
+// condition, e.g. "partly-cloudy".
+type waybarRenderer struct{}
+
+// waybarOutput is the object Waybar expects from a custom module.
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+// NewWaybarRenderer returns the renderer behind --waybar.
+func NewWaybarRenderer() Renderer {
+	return waybarRenderer{}
+}
+
+func (r waybarRenderer) Current(w io.Writer, v *CurrentView) error {
+	return r.Both(w, v, nil)
+}
+
+func (r waybarRenderer) Hourly(w io.Writer, v *HourlyView) error {
+	return r.Both(w, nil, v)
+}
+
+func (waybarRenderer) Daily(w io.Writer, v *DailyView) error {
+	if len(v.Days) == 0 {
+		return writeWaybar(w, waybarOutput{Text: "-", Tooltip: v.Location})
+	}
+	d := v.Days[0]
+	lines := []string{v.Location}
+	for _, day := range v.Days {
+		lines = append(lines, fmt.Sprintf("%s  %.0f/%.0f%s  %s", day.Date, day.TempMax, day.TempMin, v.Units.Temp, description(day.Icon, day.Condition)))
+	}
+	return writeWaybar(w, waybarOutput{
+		Text:    fmt.Sprintf("%s %.0f/%.0f%s", d.Icon, d.TempMax, d.TempMin, v.Units.Temp),
+		Tooltip: strings.Join(lines, "\n"),
+		Class:   conditionClass(d.Code),
+	})
+}
+
+// Both shows current conditions in the text, falling back to the first hour.
+func (waybarRenderer) Both(w io.Writer, c *CurrentView, h *HourlyView) error {
+	var now Conditions
+	var meta Meta
+	switch {
+	case c != nil:
+		now, meta = c.Conditions, c.Meta
+	case h != nil && len(h.Hours) > 0:
+		now, meta = h.Hours[0], h.Meta
+	default:
+		return writeWaybar(w, waybarOutput{Text: "-"})
+	}
+	un := meta.Units
+	lines := []string{
+		meta.Location,
+		fmt.Sprintf("%s, %.1f %s", description(now.Icon, now.Condition), now.Temp, un.Temp),
+		fmt.Sprintf("Humidity %.0f %%", now.Humidity),
+		fmt.Sprintf("Wind %.1f %s %s", now.Wind, un.Wind, now.Compass),
+		fmt.Sprintf("Pressure %s %s", formatPressure(now.Pressure, un.Pressure), un.Pressure),
+	}
+	if h != nil {
+		lines = append(lines, "")
+		for _, hour := range h.Hours {
+			lines = append(lines, fmt.Sprintf("%s  %5.1f%s  %s", hour.Time.Format("15:04"), hour.Temp, un.Temp, description(hour.Icon, hour.Condition)))
+		}
+	}
+	return writeWaybar(w, waybarOutput{
+		Text:    fmt.Sprintf("%s %.0f%s", now.Icon, now.Temp, un.Temp),
+		Tooltip: strings.Join(lines, "\n"),
+		Class:   conditionClass(now.Code),
+	})
+}
+
+// Compare shows every location's icon and temperature in the text, from its
+// current conditions or first hour, and details in the tooltip.
+func (waybarRenderer) Compare(w io.Writer, v *CompareView) error {
+	var texts, lines []string
+	class := ""
+	for _, l := range v.fetched() {
+		var now Conditions
+		var meta Meta
+		switch {
+		case l.Current != nil:
+			now, meta = l.Current.Conditions, l.Current.Meta
+		case len(l.Hourly.Hours) > 0:
+			now, meta = l.Hourly.Hours[0], l.Hourly.Meta
+		default:
+			continue
+		}
+		if class == "" {
+			class = conditionClass(now.Code)
+		}
+		texts = append(texts, fmt.Sprintf("%s %.0f%s", now.Icon, now.Temp, meta.Units.Temp))
+		lines = append(lines, fmt.Sprintf("%s: %s, %.1f %s", shortName(l.title()), description(now.Icon, now.Condition), now.Temp, meta.Units.Temp))
+	}
+	if len(texts) == 0 {
+		return writeWaybar(w, waybarOutput{Text: "-"})
+	}
+	return writeWaybar(w, waybarOutput{
+		Text:    strings.Join(texts, "  "),
+		Tooltip: strings.Join(lines, "\n"),
+		Class:   class,
+	})
+}
+
+func writeWaybar(w io.Writer, out waybarOutput) error {
+	out.Text = strings.TrimSpace(out.Text)
+	return json.NewEncoder(w).Encode(out)
+}
+
+// conditionClass is the CSS class of a weather code, named after its default
+// condition so that styles keep working when the text is overridden.
+func conditionClass(code int) string {
+	return strings.ReplaceAll(strings.ToLower(api.ConditionFromWMO(code).String()), " ", "-")
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"math"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/model"
+)
+
+// The view model is what every output format renders: the provider models
+// flattened into one record per instant, with times in the display time
+// zone and missing values dropped.
+
+// Meta describes where a view's values are for and in which units.
+type Meta struct {
+	Location  string    `json:"location,omitempty" yaml:"location,omitempty"`
+	Latitude  float64   `json:"latitude" yaml:"latitude"`
+	Longitude float64   `json:"longitude" yaml:"longitude"`
+	TimeZone  string    `json:"time_zone,omitempty" yaml:"time_zone,omitempty"`
+	Units     UnitsView `json:"units" yaml:"units"`
+}
+
+// UnitsView labels the unit of each quantity, e.g. "°C" or "km/h".
+type UnitsView struct {
+	Temp      string            `json:"temperature" yaml:"temperature"`
+	Wind      string            `json:"wind_speed" yaml:"wind_speed"`
+	Precip    string            `json:"precipitation" yaml:"precipitation"`
+	Pressure  string            `json:"pressure" yaml:"pressure"`
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// unitOf returns the unit label of a column or variable.
+func (u UnitsView) unitOf(name string) string {
+	switch name {
+	case "temperature":
+		return u.Temp
+	case "wind_speed":
+		return u.Wind
+	case "precipitation":
+		return u.Precip
+	case "pressure":
+		return u.Pressure
+	}
+	return u.Variables[name]
+}
+
+// Conditions are the values for one instant, current or hourly.
+type Conditions struct {
+	Time      time.Time          `json:"time" yaml:"time"`
+	Temp      float64            `json:"temperature" yaml:"temperature"`
+	Humidity  float64            `json:"humidity" yaml:"humidity"`
+	Wind      float64            `json:"wind_speed" yaml:"wind_speed"`
+	WindDir   float64            `json:"wind_direction" yaml:"wind_direction"`
+	Compass   string             `json:"compass" yaml:"compass"`
+	Pressure  float64            `json:"pressure" yaml:"pressure"`
+	Code      int                `json:"weather_code" yaml:"weather_code"`
+	Condition string             `json:"condition" yaml:"condition"`
+	Icon      string             `json:"-" yaml:"-"`
+	Vars      map[string]float64 `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// Day is one day of a daily forecast. Sunrise and Sunset are local HH:MM.
+type Day struct {
+	Date       string  `json:"date" yaml:"date"`
+	TempMax    float64 `json:"temperature_max" yaml:"temperature_max"`
+	TempMin    float64 `json:"temperature_min" yaml:"temperature_min"`
+	Precip     float64 `json:"precipitation" yaml:"precipitation"`
+	PrecipProb float64 `json:"precipitation_probability" yaml:"precipitation_probability"`
+	WindMax    float64 `json:"wind_speed_max" yaml:"wind_speed_max"`
+	Sunrise    string  `json:"sunrise" yaml:"sunrise"`
+	Sunset     string  `json:"sunset" yaml:"sunset"`
+	Code       int     `json:"weather_code" yaml:"weather_code"`
+	Condition  string  `json:"condition" yaml:"condition"`
+	Icon       string  `json:"-" yaml:"-"`
+}
+
+// CurrentView is the view of current conditions.
+type CurrentView struct {
+	Meta       `yaml:",inline"`
+	Conditions `yaml:",inline"`
+	vars       []string
+}
+
+// HourlyView is the view of an hourly forecast.
+type HourlyView struct {
+	Meta  `yaml:",inline"`
+	Hours []Conditions `json:"hours" yaml:"hours"`
+	vars  []string
+}
+
+// DailyView is the view of a daily forecast.
+type DailyView struct {
+	Meta `yaml:",inline"`
+	Days []Day `json:"days" yaml:"days"`
+}
+
+// NewCurrentView builds the view of current conditions. Times are shown in
+// the time zone configured in cfg (local time when cfg is nil).
+func NewCurrentView(weather *model.WeatherResponse, cfg *config.Config) *CurrentView {
+	loc, _ := displayLocation(cfg)
+	v := &CurrentView{
+		Meta: newMeta(weather.Location, weather.Latitude, weather.Longitude, "", weather.Units),
+		Conditions: newConditions(weather.Current.Time, loc, weather.Current.Temperature, weather.Current.Humidity,
+			weather.Current.Windspeed, weather.Current.Winddirection, weather.Current.Pressure, weather.Current.Weathercode, cfg),
+		vars: variableNames(weather.Variables),
+	}
+	for _, name := range v.vars {
+		if val := weather.Variables[name]; !math.IsNaN(val) {
+			if v.Vars == nil {
+				v.Vars = make(map[string]float64)
+			}
+			v.Vars[name] = val
+		}
+	}
+	return v
+}
+
+// NewHourlyView builds the view of the first hours of an hourly forecast
+// (all of them when hours <= 0), in the time zone configured in cfg.
+func NewHourlyView(forecast *model.HourlyForecast, hours int, cfg *config.Config) *HourlyView {
+	loc, locName := displayLocation(cfg)
+	v := &HourlyView{
+		Meta:  newMeta(forecast.Location, forecast.Latitude, forecast.Longitude, locName, forecast.Units),
+		Hours: []Conditions{},
+		vars:  variableNames(forecast.Series),
+	}
+	h := forecast.Hourly
+	limit := len(h.Time)
+	if hours > 0 && hours < limit {
+		limit = hours
+	}
+	for i := 0; i < limit; i++ {
+		if _, err := ParseHour(h.Time[i]); err != nil {
+			log.Logger.Warnw("Failed to parse time", "value", h.Time[i], "error", err)
+			continue
+		}
+		c := newConditions(h.Time[i], loc, h.Temperature[i], h.Humidity[i], h.Windspeed[i], h.Winddirection[i], h.Pressure[i], h.Weathercode[i], cfg)
+		for _, name := range v.vars {
+			if series := forecast.Series[name]; i < len(series) && !math.IsNaN(series[i]) {
+				if c.Vars == nil {
+					c.Vars = make(map[string]float64)
+				}
+				c.Vars[name] = series[i]
+			}
+		}
+		v.Hours = append(v.Hours, c)
+	}
+	return v
+}
+
+// NewDailyView builds the view of the first days of a daily forecast (all
+// of them when days <= 0).
+func NewDailyView(forecast *model.DailyForecast, days int, cfg *config.Config) *DailyView {
+	v := &DailyView{
+		Meta: newMeta(forecast.Location, forecast.Latitude, forecast.Longitude, forecast.Timezone, forecast.Units),
+		Days: []Day{},
+	}
+	d := forecast.Daily
+	limit := len(d.Time)
+	if days > 0 && days < limit {
+		limit = days
+	}
+	for i := 0; i < limit; i++ {
+		if _, err := time.Parse("2006-01-02", d.Time[i]); err != nil {
+			log.Logger.Warnw("Failed to parse date", "value", d.Time[i], "error", err)
+			continue
+		}
+		cond, icon := describeCode(d.Weathercode[i], cfg)
+		v.Days = append(v.Days, Day{
+			Date:       d.Time[i],
+			TempMax:    d.TemperatureMax[i],
+			TempMin:    d.TemperatureMin[i],
+			Precip:     d.PrecipitationSum[i],
+			PrecipProb: d.PrecipitationProbability[i],
+			WindMax:    d.WindspeedMax[i],
+			Sunrise:    clockTime(d.Sunrise[i]),
+			Sunset:     clockTime(d.Sunset[i]),
+			Code:       d.Weathercode[i],
+			Condition:  cond,
+			Icon:       icon,
+		})
+	}
+	return v
+}
+
+func newMeta(location string, lat, lon float64, tz string, u model.Units) Meta {
+	u = unitLabels(u)
+	return Meta{
+		Location:  location,
+		Latitude:  lat,
+		Longitude: lon,
+		TimeZone:  tz,
+		Units: UnitsView{
+			Temp:      u.Temperature,
+			Wind:      u.WindSpeed,
+			Precip:    u.Precipitation,
+			Pressure:  u.Pressure,
+			Variables: u.Variables,
+		},
+	}
+}
+
+func newConditions(ts string, loc *time.Location, temp, humidity, wind, windDir, pressure float64, code int, cfg *config.Config) Conditions {
+	var t time.Time
+	if parsed, err := ParseHour(ts); err == nil {
+		t = parsed.In(loc)
+	}
+	cond, icon := describeCode(code, cfg)
+	return Conditions{
+		Time:      t,
+		Temp:      temp,
+		Humidity:  humidity,
+		Wind:      wind,
+		WindDir:   windDir,
+		Compass:   degreesToCompass(windDir),
+		Pressure:  pressure,
+		Code:      code,
+		Condition: cond,
+		Icon:      icon,
+	}
+}
+
+// describeCode returns the text and icon of a WMO weather code, using the
+// icon set and overrides in cfg (emoji when cfg is nil).
+func describeCode(code int, cfg *config.Config) (condition, icon string) {
+	set := api.IconsEmoji
+	if cfg != nil {
+		if s, err := api.ParseIconSet(cfg.Icons); err == nil {
+			set = s
+		}
+	}
+	c := api.ConditionFromWMO(code)
+	condition, icon = c.String(), c.Icon(set)
+	if cfg == nil {
+		return condition, icon
+	}
+	if o, ok := cfg.Conditions[code]; ok {
+		if o.Text != "" {
+			condition = o.Text
+		}
+		if o.Icon != "" && set != api.IconsNone {
+			icon = o.Icon
+		}
+	}
+	return condition, icon
+}
+
+// description is the icon and text shown in the Conditions column.
+func description(icon, condition string) string {
+	if icon == "" {
+		return condition
+	}
+	return icon + " " + condition
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/model"
+	"goweather/internal/ui"
+)
+
+// ANSI sequences used to redraw in place.
+const (
+	enterAltScreen = "\033[?1049h\033[?25l" // alternate screen, hidden cursor
+	leaveAltScreen = "\033[?25h\033[?1049l"
+	clearScreen    = "\033[H\033[2J"
+)
+
+// statusInterval is how often the "last updated" line is redrawn between refreshes.
+const statusInterval = 30 * time.Second
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	Interval time.Duration // how often data is fetched again
+	Timeout  time.Duration // deadline of each refresh, 0 for none
+	Renderer Renderer
+	Theme    ui.Theme
+	// Screen redraws in place on the terminal's alternate screen. Without
+	// it every refresh is appended to out, e.g. for logging to a file.
+	Screen bool
+}
+
+// Watch shows current conditions and the hourly forecast and refreshes
+// them every opts.Interval until ctx is cancelled, which is not an error.
+// Only the first draw may use cached data: later refreshes ask the provider,
+// as data cached for cache_duration would otherwise hide updates for up to
+// another interval. When a refresh fails the previous data stays on
+// screen with a warning.
+func Watch(ctx context.Context, out io.Writer, f *BothFetcher, opts WatchOptions, cfg *config.Config) error {
+	if opts.Screen {
+		fmt.Fprint(out, enterAltScreen)
+		defer fmt.Fprint(out, leaveAltScreen)
+	}
+
+	var current *model.WeatherResponse
+	var hourly *model.HourlyForecast
+	var curErr, hrsErr error
+	fetch := f.Fetch
+	refresh := func() {
+		cur, hrs, cErr, hErr := fetch(ctx, opts.Timeout)
+		if cErr == nil {
+			current = cur
+		}
+		if hErr == nil {
+			hourly = hrs
+		}
+		curErr, hrsErr = cErr, hErr
+		if cErr != nil || hErr != nil {
+			log.Logger.Warnw("Watch refresh failed", "current_error", cErr, "hourly_error", hErr)
+		}
+	}
+	draw := func() error {
+		var buf bytes.Buffer
+		if opts.Screen {
+			buf.WriteString(clearScreen)
+		}
+		writeWatchStatus(&buf, f, opts)
+		var curView *CurrentView
+		var hrsView *HourlyView
+		if current != nil {
+			curView = NewCurrentView(current, cfg)
+		}
+		if hourly != nil {
+			hrsView = NewHourlyView(hourly, f.Hours, cfg)
+		}
+		if err := opts.Renderer.Both(&buf, curView, hrsView); err != nil {
+			return err
+		}
+		theme := opts.Theme
+		for _, failure := range []struct {
+			what string
+			err  error
+		}{{"current weather", curErr}, {"hourly forecast", hrsErr}} {
+			if failure.err != nil && ctx.Err() == nil {
+				fmt.Fprintf(&buf, "%s⚠ Could not refresh the %s: %v%s\n", theme.Yellow, failure.what, failure.err, theme.Reset)
+			}
+		}
+		_, err := out.Write(buf.Bytes())
+		return err
+	}
+
+	refresh()
+	if err := draw(); err != nil {
+		return err
+	}
+	fetch = f.Refresh
+
+	refreshTicker := time.NewTicker(opts.Interval)
+	defer refreshTicker.Stop()
+	statusTicker := time.NewTicker(statusInterval)
+	defer statusTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-refreshTicker.C:
+			refresh()
+		case <-statusTicker.C:
+			if !opts.Screen {
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := draw(); err != nil {
+			return err
+		}
+	}
+}
+
+// writeWatchStatus writes the line above the tables.
+func writeWatchStatus(w io.Writer, f *BothFetcher, opts WatchOptions) {
+	theme := opts.Theme
+	updated := "never"
+	if t, ok := f.Updated(); ok {
+		updated = updatedAgo(time.Since(t))
+	}
+	fmt.Fprintf(w, "%s%s%s %s· refreshing every %s · last updated %s", theme.Bold, f.Coords.Label(), theme.Reset,
+		theme.Gray, shortDuration(opts.Interval), updated)
+	if opts.Screen {
+		fmt.Fprint(w, " · Ctrl-C to quit")
+	}
+	fmt.Fprintf(w, "%s\n", theme.Reset)
+}
+
+// shortDuration drops zero trailing units: 10m rather than 10m0s.
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// updatedAgo describes an age in whole minutes, e.g. "3 min ago".
+func updatedAgo(age time.Duration) string {
+	minutes := int(age.Minutes())
+	switch {
+	case minutes < 1:
+		return "just now"
+	case minutes < 60:
+		return fmt.Sprintf("%d min ago", minutes)
+	default:
+		return fmt.Sprintf("%d h %d min ago", minutes/60, minutes%60)
+	}
+}
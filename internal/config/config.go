@@ -4,6 +4,9 @@ import (
 	"os"
 	"time"
 
+	"goweather/internal/ui"
+	"goweather/internal/units"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -11,14 +14,80 @@ type Config struct {
 	City          string        `yaml:"city"`
 	Hours         int           `yaml:"hours"`
 	Emoji         bool          `yaml:"emoji"`
-	Color         string        `yaml:"color"`
+	Color         string        `yaml:"color"` // auto | dark | light | none | a name from themes | a .yaml theme file
 	Verbose       bool          `yaml:"verbose"`
 	ForecastMode  string        `yaml:"forecast_mode"`
 	LogPath       string        `yaml:"log_path"`
 	CacheDuration time.Duration `yaml:"cache_duration"`
 	TimeZone      string        `yaml:"time_zone"` // 🆕 added
+	Provider      string        `yaml:"provider"`  // open-meteo | met-norway
+	API           APIConfig     `yaml:"api"`
+	Units         UnitsConfig   `yaml:"units"`
+	Variables     []string      `yaml:"variables"` // optional variables, e.g. apparent_temperature, uv_index
+	Geocode       GeocodeConfig `yaml:"geocode"`
+	Locations     Locations     `yaml:"locations"` // saved places usable as --city
+	Icons         string        `yaml:"icons"`     // emoji | nerdfont | ascii | none
+	// Per WMO weather code replacements of the condition text and icon
+	Conditions map[int]ConditionOverride `yaml:"conditions"`
+	// User-defined color themes selectable with color or --color
+	Themes      map[string]ui.ThemeSpec `yaml:"themes"`
+	ColorScales ColorScalesConfig       `yaml:"color_scales"`
+}
+
+// ColorScalesConfig colors values in the terminal tables by magnitude.
+type ColorScalesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Legend  bool `yaml:"legend"` // print a legend below each table
+	// Scales by variable name (temperature, wind_speed, precipitation,
+	// uv_index, ...); they replace the built-in scale of the same name
+	Scales ui.Scales `yaml:",inline"`
+}
+
+// ConditionOverride replaces the text and/or icon shown for a weather code.
+type ConditionOverride struct {
+	Text string `yaml:"text,omitempty"`
+	Icon string `yaml:"icon,omitempty"`
+}
+
+// GeocodeConfig controls how geocoding results are remembered.
+type GeocodeConfig struct {
+	CacheTTL time.Duration `yaml:"cache_ttl"` // 0 keeps cached locations forever
+}
+
+// UnitsConfig selects a unit system plus optional per-quantity overrides.
+type UnitsConfig struct {
+	System      string `yaml:"system,omitempty"` // metric | imperial
+	units.Units `yaml:",inline"`
+}
+
+// APIConfig controls how upstream weather and geocoding APIs are reached.
+type APIConfig struct {
+	ForecastURL    string        `yaml:"forecast_url"`  // empty = provider default
+	GeocodingURL   string        `yaml:"geocoding_url"` // empty = Open-Meteo geocoding
+	ProxyURL       string        `yaml:"proxy_url"`     // empty = HTTP(S)_PROXY environment
+	Timeout        time.Duration `yaml:"timeout"`
+	UserAgent      string        `yaml:"user_agent"`
+	Retry          RetryConfig   `yaml:"retry"`
+	CircuitBreaker BreakerConfig `yaml:"circuit_breaker"`
+}
+
+// RetryConfig controls retries of failed upstream requests.
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
+	Jitter      bool          `yaml:"jitter"`
+}
+
+// BreakerConfig controls the per-host upstream circuit breaker.
+type BreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"` // 0 disables the breaker
+	Cooldown         time.Duration `yaml:"cooldown"`
 }
 
+// Path is the configuration file read by Load.
+const Path = "config.yaml"
+
 // Load reads configuration from config.yaml (or sets defaults)
 func Load() (*Config, error) {
 	cfg := &Config{
@@ -31,9 +100,25 @@ func Load() (*Config, error) {
 		LogPath:       "",
 		CacheDuration: 10 * time.Minute,
 		TimeZone:      "local", // 🆕 default (system local)
+		Provider:      "open-meteo",
+		Units:         UnitsConfig{System: "metric"},
+		ColorScales:   ColorScalesConfig{Enabled: true},
+		API: APIConfig{
+			Timeout: 10 * time.Second,
+			Retry: RetryConfig{
+				MaxAttempts: 3,
+				BaseDelay:   time.Second,
+				MaxDelay:    30 * time.Second,
+				Jitter:      true,
+			},
+			CircuitBreaker: BreakerConfig{
+				FailureThreshold: 5,
+				Cooldown:         30 * time.Second,
+			},
+		},
 	}
 
-	file, err := os.ReadFile("config.yaml")
+	file, err := os.ReadFile(Path)
 	if err == nil {
 		if err := yaml.Unmarshal(file, cfg); err != nil {
 			return nil, err
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Location is a saved place under `locations:` in config.yaml, given either
+// as a city name (with optional filters) or as coordinates.
+type Location struct {
+	Label     string      `yaml:"label,omitempty"` // display name, defaults to the geocoded place
+	City      string      `yaml:"city,omitempty"`
+	Country   string      `yaml:"country,omitempty"`
+	Admin1    string      `yaml:"admin1,omitempty"`
+	Latitude  *float64    `yaml:"lat,omitempty"`
+	Longitude *float64    `yaml:"lon,omitempty"`
+	TimeZone  string      `yaml:"time_zone,omitempty"`
+	Units     UnitsConfig `yaml:"units,omitempty"`
+}
+
+// HasCoordinates reports whether the location is pinned to lat/lon.
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// Validate checks that the location says where it is.
+func (l Location) Validate() error {
+	if (l.Latitude == nil) != (l.Longitude == nil) {
+		return fmt.Errorf("lat and lon must be set together")
+	}
+	if l.City == "" && !l.HasCoordinates() {
+		return fmt.Errorf("either city or lat/lon is required")
+	}
+	return nil
+}
+
+// Locations maps saved location names to places.
+type Locations map[string]Location
+
+// Lookup finds a saved location by name, ignoring case and surrounding spaces.
+func (ls Locations) Lookup(name string) (string, Location, bool) {
+	name = strings.TrimSpace(name)
+	for key, l := range ls {
+		if strings.EqualFold(key, name) {
+			return key, l, true
+		}
+	}
+	return "", Location{}, false
+}
+
+// Names returns the saved location names in alphabetical order.
+func (ls Locations) Names() []string {
+	names := make([]string, 0, len(ls))
+	for name := range ls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyLocation makes a saved location's time zone and units the defaults.
+// A unit system replaces the configured one; per-quantity units refine it.
+func (c *Config) ApplyLocation(l Location) {
+	if l.TimeZone != "" {
+		c.TimeZone = l.TimeZone
+	}
+	if l.Units.System != "" {
+		c.Units = l.Units
+	} else {
+		c.Units.Units = c.Units.Units.With(l.Units.Units)
+	}
+}
+
+// SaveLocation adds or replaces a saved location in config.yaml, keeping the
+// rest of the file (including comments) as it is.
+func SaveLocation(name string, l Location) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	var value yaml.Node
+	if err := value.Encode(l); err != nil {
+		return err
+	}
+	return editLocations(func(locs *yaml.Node) bool {
+		if i := mappingIndex(locs, name); i >= 0 {
+			locs.Content[i+1] = &value
+		} else {
+			locs.Content = append(locs.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, &value)
+		}
+		return true
+	})
+}
+
+// RemoveLocation deletes a saved location from config.yaml. It reports
+// whether the location existed.
+func RemoveLocation(name string) (bool, error) {
+	found := false
+	err := editLocations(func(locs *yaml.Node) bool {
+		if i := mappingIndex(locs, name); i >= 0 {
+			locs.Content = append(locs.Content[:i], locs.Content[i+2:]...)
+			found = true
+		}
+		return found
+	})
+	return found, err
+}
+
+// editLocations loads config.yaml as a node tree, lets fn change the
+// locations mapping and writes the file back atomically when fn reports a change.
+func editLocations(fn func(locs *yaml.Node) bool) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", Path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", Path)
+	}
+
+	var locs *yaml.Node
+	if i := mappingIndex(root, "locations"); i >= 0 {
+		locs = root.Content[i+1]
+		if locs.Kind != yaml.MappingNode {
+			// "locations:" with no entries decodes as a null scalar
+			*locs = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+	} else {
+		locs = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "locations"}, locs)
+	}
+	if !fn(locs) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(Path), filepath.Base(Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), Path)
+}
+
+// mappingIndex returns the index of key (case-insensitive) in a mapping
+// node's content, or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if strings.EqualFold(m.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
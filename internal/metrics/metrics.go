@@ -0,0 +1,172 @@
+// Package metrics derives comfort metrics (dew point, heat index, wind chill,
+// humidex, absolute humidity) from temperature, humidity and wind speed.
+//
+// The formulas work in °C, % and km/h and return NaN outside the range in
+// which they are defined.
+package metrics
+
+import (
+	"math"
+
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// Magnus coefficients over water (Sonntag 1990), valid from -45 to 60 °C.
+const (
+	magnusA = 17.62
+	magnusB = 243.12 // °C
+
+	magnusMin = -45.0
+	magnusMax = 60.0
+)
+
+// DewPoint returns the dew point in °C of air at t °C and rh % relative
+// humidity, using the Magnus formula.
+func DewPoint(t, rh float64) float64 {
+	if !magnusRange(t, rh) || rh == 0 {
+		return math.NaN()
+	}
+	g := math.Log(rh/100) + magnusA*t/(magnusB+t)
+	return magnusB * g / (magnusA - g)
+}
+
+// HeatIndex returns the US National Weather Service heat index in °C. It is
+// defined from 80 °F (26.7 °C) up; below that it is NaN.
+func HeatIndex(t, rh float64) float64 {
+	if math.IsNaN(t) || rh < 0 || rh > 100 || math.IsNaN(rh) {
+		return math.NaN()
+	}
+	f := t*9/5 + 32
+	if f < 80 {
+		return math.NaN()
+	}
+	// Steadman's simple formula, good enough while it averages below 80 °F
+	hi := 0.5 * (f + 61 + (f-68)*1.2 + rh*0.094)
+	if (hi+f)/2 >= 80 {
+		hi = -42.379 + 2.04901523*f + 10.14333127*rh -
+			0.22475541*f*rh - 0.00683783*f*f - 0.05481717*rh*rh +
+			0.00122874*f*f*rh + 0.00085282*f*rh*rh - 0.00000199*f*f*rh*rh
+		switch {
+		case rh < 13 && f <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(f-95))/17)
+		case rh > 85 && f <= 87:
+			hi += (rh - 85) / 10 * (87 - f) / 5
+		}
+	}
+	return (hi - 32) * 5 / 9
+}
+
+// WindChill returns the wind chill index in °C used in the US and Canada
+// since 2001 for air at t °C and a wind of v km/h at 10 m. It is defined at
+// 10 °C and below with wind of at least 4.8 km/h (3 mph); otherwise it is NaN.
+func WindChill(t, v float64) float64 {
+	if math.IsNaN(t) || math.IsNaN(v) || t > 10 || v < 4.8 {
+		return math.NaN()
+	}
+	p := math.Pow(v, 0.16)
+	return 13.12 + 0.6215*t - 11.37*p + 0.3965*t*p
+}
+
+// Humidex returns the Canadian humidex for air at t °C with a dew point of
+// td °C. It is defined from 20 °C up; below that it is NaN.
+func Humidex(t, td float64) float64 {
+	if math.IsNaN(t) || math.IsNaN(td) || t < 20 || td > t {
+		return math.NaN()
+	}
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+td))) // vapour pressure, hPa
+	return t + 0.5555*(e-10)
+}
+
+// AbsoluteHumidity returns the water vapour content in g/m³ of air at t °C
+// and rh % relative humidity.
+func AbsoluteHumidity(t, rh float64) float64 {
+	if !magnusRange(t, rh) {
+		return math.NaN()
+	}
+	e := 6.112 * math.Exp(17.67*t/(t+243.5)) * rh / 100 // vapour pressure, hPa
+	return 216.74 * e / (273.15 + t)
+}
+
+// FeelsLike returns the heat index when it is defined, else the wind chill,
+// else the air temperature, all in °C.
+func FeelsLike(t, rh, v float64) float64 {
+	if hi := HeatIndex(t, rh); !math.IsNaN(hi) {
+		return hi
+	}
+	if wc := WindChill(t, v); !math.IsNaN(wc) {
+		return wc
+	}
+	return t
+}
+
+// magnusRange reports whether the Magnus formula applies to t and rh.
+func magnusRange(t, rh float64) bool {
+	return t >= magnusMin && t <= magnusMax && rh >= 0 && rh <= 100
+}
+
+// Values are the metrics of one observation. Temperatures are in the unit of
+// the response they were computed from; NaN marks a metric outside its range.
+type Values struct {
+	DewPoint         float64
+	HeatIndex        float64
+	WindChill        float64
+	Humidex          float64
+	AbsoluteHumidity float64 // g/m³
+	FeelsLike        float64
+}
+
+// compute returns the metrics of air at t and a wind of v, given in the units
+// labelled tempUnit and windUnit, with rh % relative humidity.
+func compute(t, rh, v float64, tempUnit, windUnit string) Values {
+	c := convert(t, tempUnit, units.Celsius)
+	kmh := convert(v, windUnit, units.KMH)
+	td := DewPoint(c, rh)
+	back := func(c float64) float64 { return convert(c, units.Celsius, tempUnit) }
+	return Values{
+		DewPoint:         back(td),
+		HeatIndex:        back(HeatIndex(c, rh)),
+		WindChill:        back(WindChill(c, kmh)),
+		Humidex:          back(Humidex(c, td)),
+		AbsoluteHumidity: AbsoluteHumidity(c, rh),
+		FeelsLike:        back(FeelsLike(c, rh, kmh)),
+	}
+}
+
+// convert converts v between two unit labels or identifiers, treating an
+// empty label as the metric unit.
+func convert(v float64, from, to string) float64 {
+	if from == "" || to == "" || math.IsNaN(v) {
+		return v
+	}
+	out, err := units.Convert(v, from, to)
+	if err != nil {
+		return math.NaN()
+	}
+	return out
+}
+
+// Current returns the metrics of the current conditions in w.
+func Current(w *model.WeatherResponse) Values {
+	c := w.Current
+	return compute(c.Temperature, c.Humidity, c.Windspeed, w.Units.Temperature, w.Units.WindSpeed)
+}
+
+// Hourly returns the metrics of every hour in h.
+func Hourly(h *model.HourlyForecast) []Values {
+	hr := h.Hourly
+	out := make([]Values, len(hr.Time))
+	for i := range out {
+		out[i] = compute(at(hr.Temperature, i), at(hr.Humidity, i), at(hr.Windspeed, i),
+			h.Units.Temperature, h.Units.WindSpeed)
+	}
+	return out
+}
+
+// at returns s[i], or NaN when the series is short.
+func at(s []float64, i int) float64 {
+	if i < len(s) {
+		return s[i]
+	}
+	return math.NaN()
+}
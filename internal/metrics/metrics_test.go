@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"goweather/internal/api"
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+func fahrenheit(c float64) float64 { return c*9/5 + 32 }
+func celsius(f float64) float64    { return (f - 32) * 5 / 9 }
+
+func near(got, want, tol float64) bool {
+	if math.IsNaN(want) {
+		return math.IsNaN(got)
+	}
+	return math.Abs(got-want) <= tol
+}
+
+func TestDewPoint(t *testing.T) {
+	tests := []struct {
+		t, rh, want float64
+	}{
+		{20, 50, 9.3},
+		{30, 80, 26.2},
+		{-10, 90, -11.3},
+		{25, 100, 25},
+		{20, 0, math.NaN()},   // no vapour, no dew point
+		{70, 50, math.NaN()},  // above the Magnus range
+		{20, 120, math.NaN()}, // not a humidity
+	}
+	for _, tt := range tests {
+		if got := DewPoint(tt.t, tt.rh); !near(got, tt.want, 0.05) {
+			t.Errorf("DewPoint(%g, %g) = %.2f, want %.1f", tt.t, tt.rh, got, tt.want)
+		}
+	}
+}
+
+func TestHeatIndex(t *testing.T) {
+	// Values from the NWS heat index chart, in °F
+	tests := []struct {
+		f, rh, want float64
+	}{
+		{90, 70, 106},
+		{96, 65, 121},
+		{86, 90, 105},
+		{100, 10, 94}, // low humidity adjustment
+		{85, 90, 102}, // high humidity adjustment
+		{80, 40, 80},  // Steadman's simple formula
+		{79, 90, math.NaN()},
+	}
+	for _, tt := range tests {
+		got := HeatIndex(celsius(tt.f), tt.rh)
+		if !near(fahrenheit(got), tt.want, 1) {
+			t.Errorf("HeatIndex(%g °F, %g) = %.1f °F, want %.0f", tt.f, tt.rh, fahrenheit(got), tt.want)
+		}
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	// Values from the Environment Canada wind chill chart
+	tests := []struct {
+		t, v, want float64
+	}{
+		{-10, 20, -18},
+		{-20, 30, -33},
+		{0, 10, -3},
+		{5, 50, -1},
+		{11, 20, math.NaN()}, // too warm
+		{-10, 3, math.NaN()}, // too calm
+	}
+	for _, tt := range tests {
+		if got := WindChill(tt.t, tt.v); !near(got, tt.want, 0.5) {
+			t.Errorf("WindChill(%g, %g) = %.2f, want %g", tt.t, tt.v, got, tt.want)
+		}
+	}
+	// NWS chart: 0 °F with 15 mph feels like -19 °F
+	if got := fahrenheit(WindChill(celsius(0), 15*1.609344)); !near(got, -19, 0.5) {
+		t.Errorf("WindChill(0 °F, 15 mph) = %.1f °F, want -19", got)
+	}
+}
+
+func TestHumidex(t *testing.T) {
+	// Values from the Environment Canada humidex table
+	tests := []struct {
+		t, td, want float64
+	}{
+		{30, 15, 34},
+		{35, 25, 47},
+		{25, 20, 33},
+		{19, 15, math.NaN()}, // too cool
+		{25, 26, math.NaN()}, // dew point above the temperature
+	}
+	for _, tt := range tests {
+		if got := Humidex(tt.t, tt.td); !near(got, tt.want, 0.5) {
+			t.Errorf("Humidex(%g, %g) = %.2f, want %g", tt.t, tt.td, got, tt.want)
+		}
+	}
+}
+
+func TestAbsoluteHumidity(t *testing.T) {
+	tests := []struct {
+		t, rh, want float64
+	}{
+		{20, 50, 8.64},
+		{30, 80, 24.28},
+		{0, 100, 4.85},
+		{20, 0, 0},
+		{-50, 50, math.NaN()},
+	}
+	for _, tt := range tests {
+		if got := AbsoluteHumidity(tt.t, tt.rh); !near(got, tt.want, 0.01) {
+			t.Errorf("AbsoluteHumidity(%g, %g) = %.3f, want %g", tt.t, tt.rh, got, tt.want)
+		}
+	}
+}
+
+func TestFeelsLike(t *testing.T) {
+	if got := FeelsLike(celsius(90), 70, 10); !near(fahrenheit(got), 106, 1) {
+		t.Errorf("FeelsLike in the heat = %.1f °F, want the heat index 106", fahrenheit(got))
+	}
+	if got := FeelsLike(-10, 80, 20); !near(got, -18, 0.5) {
+		t.Errorf("FeelsLike in the cold = %.1f, want the wind chill -18", got)
+	}
+	if got := FeelsLike(18, 60, 20); got != 18 {
+		t.Errorf("FeelsLike in between = %.1f, want the air temperature 18", got)
+	}
+}
+
+func TestCurrentUsesResponseUnits(t *testing.T) {
+	var w model.WeatherResponse
+	w.Units = model.Units{Temperature: "°F", WindSpeed: "mph"}
+	w.Current.Temperature = 0
+	w.Current.Humidity = 50
+	w.Current.Windspeed = 15
+
+	v := Current(&w)
+	if !near(v.WindChill, -19, 0.5) {
+		t.Errorf("WindChill = %.1f °F, want -19", v.WindChill)
+	}
+	if !math.IsNaN(v.HeatIndex) || !math.IsNaN(v.Humidex) {
+		t.Errorf("HeatIndex, Humidex = %g, %g, want NaN in the cold", v.HeatIndex, v.Humidex)
+	}
+	if !near(v.FeelsLike, v.WindChill, 0) {
+		t.Errorf("FeelsLike = %.1f, want the wind chill %.1f", v.FeelsLike, v.WindChill)
+	}
+}
+
+func TestHourly(t *testing.T) {
+	var h model.HourlyForecast
+	h.Units = model.Units{Temperature: "°C", WindSpeed: "km/h"}
+	h.Hourly.Time = []string{"2026-07-01T12:00", "2026-07-01T13:00", "2026-07-01T14:00"}
+	h.Hourly.Temperature = []float64{30, 20}
+	h.Hourly.Humidity = []float64{80, 50}
+	h.Hourly.Windspeed = []float64{10, 10}
+
+	v := Hourly(&h)
+	if len(v) != 3 {
+		t.Fatalf("got %d hours, want 3", len(v))
+	}
+	if !near(v[0].DewPoint, 26.2, 0.05) || !near(v[1].DewPoint, 9.3, 0.05) {
+		t.Errorf("DewPoint = %.2f, %.2f, want 26.2, 9.3", v[0].DewPoint, v[1].DewPoint)
+	}
+	if !near(v[1].AbsoluteHumidity, 8.64, 0.01) {
+		t.Errorf("AbsoluteHumidity = %.3f, want 8.64", v[1].AbsoluteHumidity)
+	}
+	if !math.IsNaN(v[2].DewPoint) {
+		t.Errorf("DewPoint of an hour without data = %g, want NaN", v[2].DewPoint)
+	}
+}
+
+// stubProvider returns a fixed response for current conditions.
+type stubProvider struct {
+	api.Provider
+	current model.WeatherResponse
+}
+
+func (p stubProvider) Current(ctx context.Context, lat, lon float64, u units.Units, vars []string) (*model.WeatherResponse, error) {
+	w := p.current
+	return &w, nil
+}
+
+func TestProviderKeepsOutOfRangeMetrics(t *testing.T) {
+	var mild model.WeatherResponse
+	mild.Units = model.Units{Temperature: "°C", WindSpeed: "km/h"}
+	mild.Current.Temperature = 15
+	mild.Current.Humidity = 50
+	mild.Current.Windspeed = 10
+
+	w, err := WithMetrics(stubProvider{current: mild}).Current(context.Background(), 0, 0, units.Metric, []string{"heat_index", "dew_point_2m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := w.Variables["heat_index"]; !ok || !math.IsNaN(v) {
+		t.Errorf("heat_index = %g (present %v), want NaN on a mild day", v, ok)
+	}
+	if w.Units.Variables["heat_index"] == "" {
+		t.Error("heat_index has no unit label")
+	}
+	if !near(w.Variables["dew_point_2m"], 4.7, 0.05) {
+		t.Errorf("dew_point_2m = %.2f, want 4.7", w.Variables["dew_point_2m"])
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("encoding the response: %v", err)
+	}
+	if !strings.Contains(string(data), `"heat_index":null`) {
+		t.Errorf("JSON %s, want heat_index null", data)
+	}
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"math"
+
+	"goweather/internal/api"
+	"goweather/internal/model"
+	"goweather/internal/units"
+)
+
+// AbsoluteHumidityLabel is the unit label of absolute humidity.
+const AbsoluteHumidityLabel = "g/m³"
+
+// fallbacks are the upstream variables filled from the metrics when the
+// provider has no data for them, as MET Norway has none for apparent_temperature.
+var fallbacks = map[string]bool{
+	"dew_point_2m":         true,
+	"apparent_temperature": true,
+}
+
+// provider adds the derived variables to the responses of a Provider.
+type provider struct {
+	api.Provider
+}
+
+// WithMetrics wraps p so that the derived variables (see api.Variable.Derived)
+// can be requested like any other: they are left out of the upstream request
+// and computed from the response. Dew point and apparent temperature are
+// computed too when p returns none.
+func WithMetrics(p api.Provider) api.Provider {
+	return provider{p}
+}
+
+func (p provider) Current(ctx context.Context, lat, lon float64, u units.Units, vars []string) (*model.WeatherResponse, error) {
+	w, err := p.Provider.Current(ctx, lat, lon, u, upstream(vars))
+	if err != nil {
+		return nil, err
+	}
+	values := Current(w)
+	for _, name := range vars {
+		if _, ok := w.Variables[name]; ok || !computed(name) {
+			continue
+		}
+		if w.Variables == nil {
+			w.Variables = make(model.Values)
+		}
+		// NaN when out of range, so that the variable is still listed
+		w.Variables[name] = values.get(name)
+		setLabel(&w.Units, name, u)
+	}
+	return w, nil
+}
+
+func (p provider) Hourly(ctx context.Context, lat, lon float64, days int, u units.Units, vars []string) (*model.HourlyForecast, error) {
+	h, err := p.Provider.Hourly(ctx, lat, lon, days, u, upstream(vars))
+	if err != nil {
+		return nil, err
+	}
+	var values []Values
+	for _, name := range vars {
+		if _, ok := h.Series[name]; ok || !computed(name) {
+			continue
+		}
+		if values == nil {
+			values = Hourly(h)
+		}
+		series := make(model.Series, len(values))
+		for i, v := range values {
+			series[i] = v.get(name)
+		}
+		if h.Series == nil {
+			h.Series = make(map[string]model.Series)
+		}
+		h.Series[name] = series
+		setLabel(&h.Units, name, u)
+	}
+	return h, nil
+}
+
+// upstream drops the derived variables from vars.
+func upstream(vars []string) []string {
+	var out []string
+	for _, name := range vars {
+		if !api.LookupVariable(name).Derived {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// computed reports whether the variable name can be computed here.
+func computed(name string) bool {
+	return api.LookupVariable(name).Derived || fallbacks[name]
+}
+
+// get returns the metric behind the variable name.
+func (v Values) get(name string) float64 {
+	switch name {
+	case "dew_point_2m":
+		return v.DewPoint
+	case "heat_index":
+		return v.HeatIndex
+	case "wind_chill":
+		return v.WindChill
+	case "humidex":
+		return v.Humidex
+	case "absolute_humidity":
+		return v.AbsoluteHumidity
+	case "apparent_temperature":
+		return v.FeelsLike
+	}
+	return math.NaN()
+}
+
+// setLabel records the unit label of a computed variable.
+func setLabel(labels *model.Units, name string, u units.Units) {
+	if labels.Variables == nil {
+		labels.Variables = make(map[string]string)
+	}
+	if name == "absolute_humidity" {
+		labels.Variables[name] = AbsoluteHumidityLabel
+		return
+	}
+	labels.Variables[name] = api.LookupVariable(name).Quantity.Label(u)
+}
@@ -1,8 +1,90 @@
 package model
 
+import (
+	"encoding/json"
+	"math"
+)
+
+// Units labels the unit each quantity of a response is expressed in.
+// Variables holds the labels of the optional variables, keyed by name.
+type Units struct {
+	Temperature   string            `json:"temperature"`
+	WindSpeed     string            `json:"wind_speed"`
+	Precipitation string            `json:"precipitation"`
+	Pressure      string            `json:"pressure"`
+	Variables     map[string]string `json:"variables,omitempty"`
+}
+
+// Series is an hourly series of an optional variable. Missing values are
+// NaN in Go and null in JSON.
+type Series []float64
+
+func (s Series) MarshalJSON() ([]byte, error) {
+	out := make([]*float64, len(s))
+	for i := range s {
+		if !math.IsNaN(s[i]) {
+			out[i] = &s[i]
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (s *Series) UnmarshalJSON(data []byte) error {
+	var in []*float64
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = make(Series, len(in))
+	for i, v := range in {
+		if v == nil {
+			(*s)[i] = math.NaN()
+		} else {
+			(*s)[i] = *v
+		}
+	}
+	return nil
+}
+
+// Values are the current values of optional variables by name. A variable
+// without a value is NaN in Go and null in JSON.
+type Values map[string]float64
+
+func (v Values) MarshalJSON() ([]byte, error) {
+	out := make(map[string]*float64, len(v))
+	for name, val := range v {
+		out[name] = nil
+		if !math.IsNaN(val) {
+			out[name] = &val
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (v *Values) UnmarshalJSON(data []byte) error {
+	var in map[string]*float64
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in == nil {
+		*v = nil
+		return nil
+	}
+	*v = make(Values, len(in))
+	for name, val := range in {
+		if val == nil {
+			(*v)[name] = math.NaN()
+		} else {
+			(*v)[name] = *val
+		}
+	}
+	return nil
+}
+
 type WeatherResponse struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+	Location  string  `json:"location,omitempty"`
+	Units     Units   `json:"units"`
 	Current   struct {
 		Time          string  `json:"time"`
 		Temperature   float64 `json:"temperature_2m"`
@@ -12,11 +94,16 @@ type WeatherResponse struct {
 		Pressure      float64 `json:"surface_pressure"`
 		Weathercode   int     `json:"weathercode"`
 	} `json:"current"`
+	// Optional variables requested with --vars, keyed by Open-Meteo name
+	Variables Values `json:"variables,omitempty"`
 }
 
 type HourlyForecast struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+	Location  string  `json:"location,omitempty"`
+	Timezone  string  `json:"timezone,omitempty"` // zone of Hourly.Time, UTC when empty
+	Units     Units   `json:"units"`
 	Hourly    struct {
 		Time          []string  `json:"time"`
 		Temperature   []float64 `json:"temperature_2m"`
@@ -26,6 +113,8 @@ type HourlyForecast struct {
 		Pressure      []float64 `json:"surface_pressure"`
 		Weathercode   []int     `json:"weathercode"`
 	} `json:"hourly"`
+	// Optional variables requested with --vars, keyed by Open-Meteo name
+	Series map[string]Series `json:"series,omitempty"`
 }
 
 func (h *HourlyForecast) Time() []string         { return h.Hourly.Time }
@@ -35,11 +124,34 @@ func (h *HourlyForecast) Windspeed() []float64   { return h.Hourly.Windspeed }
 func (h *HourlyForecast) Pressure() []float64    { return h.Hourly.Pressure }
 func (h *HourlyForecast) Weathercode() []int     { return h.Hourly.Weathercode }
 
+type DailyForecast struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Location  string  `json:"location,omitempty"`
+	Timezone  string  `json:"timezone"`
+	Units     Units   `json:"units"`
+	Daily     struct {
+		Time                     []string  `json:"time"`
+		TemperatureMax           []float64 `json:"temperature_2m_max"`
+		TemperatureMin           []float64 `json:"temperature_2m_min"`
+		PrecipitationSum         []float64 `json:"precipitation_sum"`
+		PrecipitationProbability []float64 `json:"precipitation_probability_max"`
+		WindspeedMax             []float64 `json:"windspeed_10m_max"`
+		Sunrise                  []string  `json:"sunrise"`
+		Sunset                   []string  `json:"sunset"`
+		Weathercode              []int     `json:"weathercode"`
+	} `json:"daily"`
+}
+
 type GeocodeResponse struct {
 	Results []struct {
-		Name      string  `json:"name"`
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
-		Country   string  `json:"country"`
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		Country     string  `json:"country"`
+		CountryCode string  `json:"country_code"`
+		Admin1      string  `json:"admin1"`
+		Population  int     `json:"population"`
+		Timezone    string  `json:"timezone"`
 	} `json:"results"`
 }
@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"goweather/internal/api"
+	"goweather/internal/cli"
+	"goweather/internal/ui"
+)
+
+// ANSI sequences used to draw the dashboard.
+const (
+	enterScreen = "\033[?1049h\033[?25l" // alternate screen, hidden cursor
+	leaveScreen = "\033[?25h\033[?1049l"
+	home        = "\033[H"
+	clearBelow  = "\033[J"
+)
+
+// Pane sizes.
+const (
+	listWidth     = 26 // location list, including the separator
+	minListScreen = 70 // narrower screens hide the location list
+	chartMinBody  = 24 // shorter screens hide the chart
+	chartHours    = 48
+	currentLines  = 5
+)
+
+// layout is how the body rows are split between the panes.
+type layout struct {
+	list, right   int // pane widths
+	hourly, chart int // rows of hourly data and chart plot rows
+}
+
+func (a *app) layout() layout {
+	l := layout{right: a.width}
+	if a.width >= minListScreen {
+		l.list = listWidth
+		l.right = a.width - listWidth - 2
+	}
+	body := a.height - 2
+	rows := body - currentLines - 2 // hourly title and column header
+	if body >= chartMinBody {
+		l.chart = min(12, body/4)
+		rows -= l.chart + 6 // title, axis and labels
+	}
+	l.hourly = max(rows, 1)
+	return l
+}
+
+// hourlyRows is how many hourly rows fit on screen.
+func (a *app) hourlyRows() int {
+	return a.layout().hourly
+}
+
+// draw redraws the whole screen.
+func (a *app) draw() {
+	l := a.layout()
+	s := &a.locs[a.selected]
+	if s.hourly != nil {
+		a.scroll = min(a.scroll, len(s.hourly.Hours)-l.hourly)
+	}
+	a.scroll = max(a.scroll, 0)
+
+	theme := a.theme()
+	var right []string
+	right = append(right, a.currentLines(s)...)
+	right = append(right, a.hourlyLines(s, l)...)
+	if l.chart > 0 {
+		right = append(right, a.chartLines(s, l)...)
+	}
+	left := a.listLines()
+
+	var buf bytes.Buffer
+	buf.WriteString(home)
+	buf.WriteString(fit(a.header(s), a.width, theme.Reset))
+	sep := theme.Gray + a.glyph("│", "|") + theme.Reset + " "
+	for i := 0; i < a.height-2; i++ {
+		buf.WriteString("\r\n")
+		line := ""
+		if l.list > 0 {
+			line = fit(at(left, i), l.list-1, theme.Reset) + " " + sep
+		}
+		buf.WriteString(fit(line+at(right, i), a.width, theme.Reset))
+	}
+	buf.WriteString("\r\n")
+	// One cell short so the terminal does not scroll
+	buf.WriteString(fit(a.footer(), a.width-1, theme.Reset))
+	buf.WriteString(clearBelow)
+	os.Stdout.Write(buf.Bytes())
+}
+
+func (a *app) header(s *locationState) string {
+	theme := a.theme()
+	loc := a.opts.Locations[a.selected].Name
+	if s.coords != nil {
+		loc = s.coords.Label()
+	}
+	status := "loading…"
+	if !s.updated.IsZero() {
+		status = "updated " + s.updated.Format("15:04")
+		if s.loading {
+			status += ", refreshing…"
+		}
+	}
+	return fmt.Sprintf("%s goweather · %s%s %s· %s units · %s theme · %s%s",
+		theme.Bold, loc, theme.Reset, theme.Gray, a.units[a.unitIdx].name, a.themes[a.themeIdx].name, status, theme.Reset)
+}
+
+func (a *app) footer() string {
+	return fmt.Sprintf("%s Tab/Shift-Tab location · ↑/↓ PgUp/PgDn Home/End scroll · u units · t theme · c chart · r refresh · q quit%s",
+		a.theme().Gray, a.theme().Reset)
+}
+
+func (a *app) listLines() []string {
+	theme := a.theme()
+	lines := []string{theme.Bold + "Locations" + theme.Reset}
+	for i, loc := range a.opts.Locations {
+		s := a.locs[i]
+		mark := " "
+		switch {
+		case s.loading:
+			mark = a.glyph("…", ".")
+		case s.err != nil:
+			mark = theme.Red + "!" + theme.Reset
+		}
+		name := fmt.Sprintf("%d %s", i+1, loc.Name)
+		if i >= 9 {
+			name = "  " + loc.Name
+		}
+		if i == a.selected {
+			lines = append(lines, fmt.Sprintf("%s%s%s %s", theme.Cyan+theme.Bold, a.glyph("▶", ">"), name, theme.Reset+mark))
+		} else {
+			lines = append(lines, fmt.Sprintf(" %s %s", name, mark))
+		}
+	}
+	return lines
+}
+
+func (a *app) currentLines(s *locationState) []string {
+	theme := a.theme()
+	lines := make([]string, 0, currentLines)
+	c := s.current
+	switch {
+	case c != nil:
+		u := c.Units
+		lines = append(lines,
+			fmt.Sprintf("%sNow%s  %s%s%s", theme.Bold, theme.Reset, theme.Green, a.condition(c.Conditions), theme.Reset),
+			fmt.Sprintf("  Temperature %s%.1f %s%s   Humidity %s%.0f %%%s",
+				theme.Cyan, c.Temp, u.Temp, theme.Reset, theme.Blue, c.Humidity, theme.Reset),
+			fmt.Sprintf("  Wind %s%.1f %s %s%s   Pressure %s%.1f %s%s",
+				theme.Yellow, c.Wind, u.Wind, c.Compass, theme.Reset, theme.Cyan, c.Pressure, u.Pressure, theme.Reset))
+		var extra []string
+		names := make([]string, 0, len(c.Vars))
+		for name := range c.Vars {
+			names = append(names, name)
+		}
+		api.SortVariables(names)
+		for _, name := range names {
+			extra = append(extra, fmt.Sprintf("%s %s%.1f %s%s", api.LookupVariable(name).Label, theme.Blue, c.Vars[name], u.Variables[name], theme.Reset))
+		}
+		lines = append(lines, "  "+strings.Join(extra, "   "))
+	case s.err == nil:
+		lines = append(lines, theme.Gray+"Fetching current conditions…"+theme.Reset)
+	}
+	for len(lines) < currentLines-1 {
+		lines = append(lines, "")
+	}
+	if s.err != nil {
+		lines = append(lines, fmt.Sprintf("%s⚠ %v%s", theme.Yellow, s.err, theme.Reset))
+	} else {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func (a *app) hourlyLines(s *locationState, l layout) []string {
+	theme := a.theme()
+	h := s.hourly
+	if h == nil {
+		lines := make([]string, l.hourly+2)
+		lines[0] = theme.Bold + "Hourly forecast" + theme.Reset
+		return lines
+	}
+	end := min(a.scroll+l.hourly, len(h.Hours))
+	u := h.Units
+	lines := []string{
+		fmt.Sprintf("%sHourly forecast%s %s(%s, %d–%d of %d)%s", theme.Bold, theme.Reset, theme.Gray,
+			h.TimeZone, a.scroll+1, end, len(h.Hours), theme.Reset),
+		fmt.Sprintf("%s%-10s %9s %6s %14s %15s  %s%s", theme.Bold, "Time", "Temp ("+u.Temp+")", "Hum %",
+			"Wind ("+u.Wind+")", "Pressure ("+u.Pressure+")", "Conditions", theme.Reset),
+	}
+	now := a.nowRow()
+	for i := a.scroll; i < end; i++ {
+		c := h.Hours[i]
+		timeColor := theme.Gray
+		if i == now {
+			timeColor = theme.Bold
+		}
+		lines = append(lines, fmt.Sprintf("%s%-10s%s %s%9.1f%s %s%6.0f%s %s%10.1f %-3s%s %s%15.1f%s  %s%s%s",
+			timeColor, c.Time.Format("Mon 15:04"), theme.Reset,
+			theme.Cyan, c.Temp, theme.Reset,
+			theme.Blue, c.Humidity, theme.Reset,
+			theme.Yellow, c.Wind, c.Compass, theme.Reset,
+			theme.Cyan, c.Pressure, theme.Reset,
+			theme.Green, a.condition(c), theme.Reset))
+	}
+	for len(lines) < l.hourly+2 {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// chartLines draws the selected series for the hours from the top row of
+// the table on.
+func (a *app) chartLines(s *locationState, l layout) []string {
+	if s.hourly == nil || len(s.hourly.Hours) == 0 {
+		return nil
+	}
+	v := *s.hourly
+	v.Location = ""
+	v.Hours = v.Hours[a.scroll:min(a.scroll+chartHours, len(v.Hours))]
+	var buf bytes.Buffer
+	if err := cli.PrintChart(&buf, &v, chartSeries[a.chartIdx], a.theme(), l.right, l.chart); err != nil {
+		return []string{err.Error()}
+	}
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+// condition describes a weather code with its icon, if any.
+func (a *app) condition(c cli.Conditions) string {
+	if c.Icon != "" {
+		return c.Icon + " " + c.Condition
+	}
+	return c.Condition
+}
+
+// glyph picks the Unicode or the ASCII form of a symbol.
+func (a *app) glyph(unicode, ascii string) string {
+	if ui.SupportsUTF8() {
+		return unicode
+	}
+	return ascii
+}
+
+// at returns lines[i], or "" past the end.
+func at(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"io"
+	"strings"
+)
+
+// Keys decoded from terminal input. Printable keys are passed as themselves.
+const (
+	keyTab      = "tab"
+	keyBackTab  = "backtab"
+	keyUp       = "up"
+	keyDown     = "down"
+	keyPageUp   = "pgup"
+	keyPageDown = "pgdn"
+	keyHome     = "home"
+	keyEnd      = "end"
+	keyQuit     = "quit"
+)
+
+// escapeKeys maps escape sequences sent by common terminals to keys.
+var escapeKeys = map[string]string{
+	"\x1b[Z":  keyBackTab,
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[4~": keyEnd,
+}
+
+// readKeys decodes key presses from r and sends them to keys until r fails.
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, k := range decodeKeys(string(buf[:n])) {
+			keys <- k
+		}
+	}
+}
+
+// decodeKeys splits one read into keys; unknown escape sequences are dropped.
+func decodeKeys(in string) []string {
+	var out []string
+	for len(in) > 0 {
+		switch c := in[0]; {
+		case c == '\x1b':
+			matched := false
+			for seq, k := range escapeKeys {
+				if strings.HasPrefix(in, seq) {
+					out, in, matched = append(out, k), in[len(seq):], true
+					break
+				}
+			}
+			if !matched {
+				if len(in) == 1 {
+					out = append(out, keyQuit) // a lone Esc
+				}
+				in = skipEscape(in)
+			}
+		case c == '\t':
+			out, in = append(out, keyTab), in[1:]
+		case c == 3 || c == 4: // Ctrl-C, Ctrl-D
+			out, in = append(out, keyQuit), in[1:]
+		default:
+			out, in = append(out, in[:1]), in[1:]
+		}
+	}
+	return out
+}
+
+// skipEscape drops one escape sequence from the start of in.
+func skipEscape(in string) string {
+	if len(in) < 2 || (in[1] != '[' && in[1] != 'O') {
+		return in[1:]
+	}
+	for i := 2; i < len(in); i++ {
+		if in[i] >= 0x40 && in[i] <= 0x7e {
+			return in[i+1:]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// runeWidth approximates how many cells a rune takes: two for emoji, none
+// for variation selectors and joiners, one otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D:
+		return 0
+	case r >= 0x1F300 && r <= 0x1FAFF, r >= 0x2600 && r <= 0x27BF:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// width is the number of cells s takes, ignoring ANSI escape sequences.
+func width(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i = escapeEnd(s, i)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += runeWidth(r)
+		i += size
+	}
+	return n
+}
+
+// fit cuts or pads s to exactly w cells, keeping ANSI escape sequences and
+// resetting colors when text was cut.
+func fit(s string, w int, reset string) string {
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			end := escapeEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := runeWidth(r)
+		if n+rw > w {
+			b.WriteString(reset)
+			break
+		}
+		b.WriteString(s[i : i+size])
+		n += rw
+		i += size
+	}
+	return b.String() + strings.Repeat(" ", max(w-n, 0))
+}
+
+// escapeEnd returns the index just after the escape sequence starting at i.
+func escapeEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i+1] != '[' {
+		return i + 1
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j + 1
+		}
+	}
+	return len(s)
+}
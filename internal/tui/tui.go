@@ -0,0 +1,311 @@
+// Package tui is the full-screen dashboard behind `goweather tui`.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"goweather/internal/api"
+	"goweather/internal/cache"
+	"goweather/internal/cli"
+	"goweather/internal/config"
+	"goweather/internal/log"
+	"goweather/internal/model"
+	"goweather/internal/ui"
+	"goweather/internal/units"
+)
+
+// ErrNotTerminal is returned when stdin or stdout is not a terminal.
+var ErrNotTerminal = errors.New("the dashboard needs an interactive terminal")
+
+// forecastHours is how many hours are fetched for the table and chart.
+const forecastHours = 72
+
+// Location is one entry of the location list.
+type Location struct {
+	Name    string
+	Key     string // location part of the cache keys
+	Resolve func(ctx context.Context) (*api.Coordinates, error)
+}
+
+// Options configures Run.
+type Options struct {
+	Locations []Location
+	Selected  int // index of the location shown first
+	Provider  api.Provider
+	Cache     *cache.Cache
+	Units     units.Units
+	Vars      []string
+	Theme     ui.Theme      // initial theme
+	ThemeName string        // its name, shown in the header
+	Interval  time.Duration // how often the shown location is refreshed
+	Timeout   time.Duration // deadline of each fetch, 0 for none
+	Config    *config.Config
+}
+
+// locationState is what is known about one location.
+type locationState struct {
+	coords   *api.Coordinates
+	current  *cli.CurrentView
+	hourly   *cli.HourlyView
+	err      error
+	loading  bool
+	unitsKey string // units current and hourly are in
+	updated  time.Time
+}
+
+// fetchResult reports a finished background fetch.
+type fetchResult struct {
+	index    int
+	unitsKey string
+	coords   *api.Coordinates
+	current  *model.WeatherResponse
+	hourly   *model.HourlyForecast
+	err      error
+}
+
+// unitChoice is a unit selection the u key cycles through.
+type unitChoice struct {
+	name  string
+	units units.Units
+}
+
+// namedTheme is a theme the t key cycles through.
+type namedTheme struct {
+	name  string
+	theme ui.Theme
+}
+
+// chartSeries are the series the c key cycles through.
+var chartSeries = []string{"temperature", "humidity", "wind_speed", "pressure"}
+
+type app struct {
+	opts     Options
+	locs     []locationState
+	selected int
+	scroll   int // first hourly row shown
+	unitIdx  int
+	units    []unitChoice
+	themeIdx int
+	themes   []namedTheme
+	chartIdx int
+	results  chan fetchResult
+	width    int
+	height   int
+}
+
+// Run shows the dashboard until q, Esc or Ctrl-C is pressed or ctx is
+// cancelled. Fetches run in the background so input is never blocked.
+func Run(ctx context.Context, opts Options) error {
+	if !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		return ErrNotTerminal
+	}
+	if len(opts.Locations) == 0 {
+		return errors.New("no locations to show; pass --city or save one with `goweather locations add`")
+	}
+	restore, err := ui.MakeRaw(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("raw terminal mode: %w", err)
+	}
+	defer restore()
+	fmt.Print(enterScreen)
+	defer fmt.Print(leaveScreen)
+
+	a := newApp(opts)
+	a.resize()
+	keys := make(chan string, 16)
+	go readKeys(os.Stdin, keys)
+	resized := make(chan os.Signal, 1)
+	ui.NotifyResize(resized)
+
+	refresh := time.NewTicker(opts.Interval)
+	defer refresh.Stop()
+	status := time.NewTicker(30 * time.Second)
+	defer status.Stop()
+
+	a.fetch(ctx, a.selected)
+	for {
+		a.draw()
+		select {
+		case <-ctx.Done():
+			return nil
+		case k, ok := <-keys:
+			if !ok || !a.handleKey(ctx, k) {
+				return nil
+			}
+		case r := <-a.results:
+			a.apply(r)
+		case <-resized:
+			a.resize()
+		case <-refresh.C:
+			a.fetch(ctx, a.selected)
+		case <-status.C:
+		}
+	}
+}
+
+func newApp(opts Options) *app {
+	a := &app{
+		opts:     opts,
+		locs:     make([]locationState, len(opts.Locations)),
+		selected: opts.Selected,
+		results:  make(chan fetchResult, len(opts.Locations)),
+	}
+	// Unit choices: the configured units first, then the systems
+	a.units = []unitChoice{{"configured", opts.Units}}
+	for _, c := range []unitChoice{{"metric", units.Metric}, {"imperial", units.Imperial}} {
+		if c.units == opts.Units {
+			a.units[0].name = c.name
+			continue
+		}
+		a.units = append(a.units, c)
+	}
+	// Themes: the initial one, then the other built-in themes
+	a.themes = []namedTheme{{opts.ThemeName, opts.Theme}}
+	emoji := map[bool]string{true: "on", false: "off"}[opts.Theme.Emoji]
+	for _, name := range []string{"dark", "light", "none"} {
+		if name != opts.ThemeName {
+			a.themes = append(a.themes, namedTheme{name, ui.GetTheme(name, emoji)})
+		}
+	}
+	return a
+}
+
+// theme is the selected theme.
+func (a *app) theme() ui.Theme {
+	return a.themes[a.themeIdx].theme
+}
+
+func (a *app) resize() {
+	w, h, err := ui.TerminalSize(os.Stdout)
+	if err != nil || w <= 0 || h <= 0 {
+		w, h = 80, 24
+	}
+	a.width, a.height = w, h
+}
+
+// handleKey applies a key press and reports whether to keep running.
+func (a *app) handleKey(ctx context.Context, k string) bool {
+	switch k {
+	case keyQuit, "q", "Q":
+		return false
+	case keyTab, "l":
+		a.selectLocation(ctx, (a.selected+1)%len(a.locs))
+	case keyBackTab, "h":
+		a.selectLocation(ctx, (a.selected+len(a.locs)-1)%len(a.locs))
+	case keyDown, "j":
+		a.scroll++
+	case keyUp, "k":
+		a.scroll--
+	case keyPageDown, " ":
+		a.scroll += a.hourlyRows()
+	case keyPageUp, "b":
+		a.scroll -= a.hourlyRows()
+	case keyHome, "g":
+		a.scroll = a.nowRow()
+	case keyEnd, "G":
+		a.scroll = 1 << 30
+	case "u":
+		a.unitIdx = (a.unitIdx + 1) % len(a.units)
+		a.fetch(ctx, a.selected)
+	case "t":
+		a.themeIdx = (a.themeIdx + 1) % len(a.themes)
+	case "c":
+		a.chartIdx = (a.chartIdx + 1) % len(chartSeries)
+	case "r":
+		a.fetch(ctx, a.selected)
+	default:
+		if len(k) == 1 && k[0] >= '1' && k[0] <= '9' && int(k[0]-'1') < len(a.locs) {
+			a.selectLocation(ctx, int(k[0]-'1'))
+		}
+	}
+	return true
+}
+
+func (a *app) selectLocation(ctx context.Context, i int) {
+	a.selected = i
+	a.scroll = a.nowRow()
+	s := a.locs[i]
+	if s.current == nil || s.unitsKey != a.unitsKey() || time.Since(s.updated) > a.opts.Interval {
+		a.fetch(ctx, i)
+	}
+}
+
+func (a *app) unitsKey() string {
+	return a.units[a.unitIdx].units.Key()
+}
+
+// fetch starts a background fetch of location i unless one is running.
+func (a *app) fetch(ctx context.Context, i int) {
+	s := &a.locs[i]
+	if s.loading {
+		return
+	}
+	s.loading = true
+	loc, coords, u := a.opts.Locations[i], s.coords, a.units[a.unitIdx].units
+	go func() {
+		r := fetchResult{index: i, unitsKey: u.Key(), coords: coords}
+		if r.coords == nil {
+			if r.coords, r.err = loc.Resolve(ctx); r.err != nil {
+				a.results <- r
+				return
+			}
+		}
+		f := &cli.BothFetcher{Provider: a.opts.Provider, Units: u, Vars: a.opts.Vars, Coords: r.coords,
+			Cache: a.opts.Cache, Key: loc.Key, Hours: forecastHours}
+		var curErr, hrsErr error
+		r.current, r.hourly, curErr, hrsErr = f.Fetch(ctx, a.opts.Timeout)
+		r.err = errors.Join(curErr, hrsErr)
+		a.results <- r
+	}()
+}
+
+// apply stores a fetch result. Data fetched in units that are no longer
+// selected is dropped and fetched again.
+func (a *app) apply(r fetchResult) {
+	s := &a.locs[r.index]
+	s.loading = false
+	s.coords = r.coords
+	s.err = r.err
+	if r.err != nil {
+		log.Logger.Warnw("Dashboard fetch failed", "location", a.opts.Locations[r.index].Name, "error", r.err)
+	}
+	if r.unitsKey != a.unitsKey() {
+		if r.index == a.selected {
+			a.fetch(context.Background(), r.index)
+		}
+		return
+	}
+	first := s.hourly == nil
+	if r.current != nil {
+		s.current = cli.NewCurrentView(r.current, a.opts.Config)
+	}
+	if r.hourly != nil {
+		s.hourly = cli.NewHourlyView(r.hourly, 0, a.opts.Config)
+	}
+	if r.current != nil || r.hourly != nil {
+		s.unitsKey = r.unitsKey
+		s.updated = time.Now()
+	}
+	if first && r.index == a.selected {
+		a.scroll = a.nowRow()
+	}
+}
+
+// nowRow is the hourly row of the current hour.
+func (a *app) nowRow() int {
+	h := a.locs[a.selected].hourly
+	if h == nil {
+		return 0
+	}
+	now := time.Now().Truncate(time.Hour)
+	for i, c := range h.Hours {
+		if !c.Time.Before(now) {
+			return i
+		}
+	}
+	return 0
+}
@@ -56,10 +56,35 @@ func SupportsColor() bool {
 	return true
 }
 
+// ColorEnabled decides whether "auto" colors output to f: never when
+// $NO_COLOR is set, always when $CLICOLOR_FORCE is set (and not 0), else
+// when f is a terminal that supports color.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	return IsTerminal(f) && SupportsColor()
+}
+
 func SupportsEmoji() bool {
 	// simple heuristic: if stdout is UTF-8 capable
-	enc := os.Getenv("LANG")
-	return utf8.ValidString(enc) && (strings.Contains(strings.ToLower(enc), "utf") || strings.Contains(strings.ToLower(enc), "utf-8"))
+	return SupportsUTF8()
+}
+
+// SupportsUTF8 reports whether the locale (LC_ALL, LC_CTYPE or LANG, in
+// that order) uses UTF-8.
+func SupportsUTF8() bool {
+	enc := os.Getenv("LC_ALL")
+	if enc == "" {
+		enc = os.Getenv("LC_CTYPE")
+	}
+	if enc == "" {
+		enc = os.Getenv("LANG")
+	}
+	return utf8.ValidString(enc) && strings.Contains(strings.ToLower(enc), "utf")
 }
 
 func GetTheme(colorOpt string, emojiOpt string) Theme {
@@ -72,7 +97,7 @@ func GetTheme(colorOpt string, emojiOpt string) Theme {
 	case "dark":
 		t = DarkTheme
 	case "auto":
-		if SupportsColor() {
+		if ColorEnabled(os.Stdout) {
 			t = DarkTheme
 		} else {
 			t = NoColor
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorDepth is how many colors a terminal can show.
+type ColorDepth int
+
+const (
+	Color16   ColorDepth = iota // the basic and bright ANSI colors
+	Color256                    // the xterm 256-color palette
+	TrueColor                   // 24-bit RGB
+)
+
+// DetectColorDepth reads the color depth from $COLORTERM (truecolor or
+// 24bit) and $TERM (*256color*).
+func DetectColorDepth() ColorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color16
+}
+
+// basicColors are the ANSI foreground codes of the named colors; bright
+// variants add 60.
+var basicColors = map[string]int{
+	"black": 30, "red": 31, "green": 32, "yellow": 33,
+	"blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+}
+
+// attributes are the text attributes a color spec may include.
+var attributes = map[string]string{
+	"bold": "1", "dim": "2", "italic": "3", "underline": "4",
+}
+
+// ParseColor turns a color spec into an ANSI escape sequence. A spec is a
+// space-separated list of attributes (bold, dim, italic, underline) and at
+// most one color: a name such as cyan or bright-red, a 256-color palette
+// index, or #rgb/#rrggbb. Colors the terminal cannot show are approximated
+// at depth. "none" and "" give no escape sequence.
+func ParseColor(spec string, depth ColorDepth) (string, error) {
+	var codes []string
+	hasColor := false
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if word == "none" || word == "default" {
+			continue
+		}
+		if a, ok := attributes[word]; ok {
+			codes = append(codes, a)
+			continue
+		}
+		if hasColor {
+			return "", fmt.Errorf("color %q: more than one color", spec)
+		}
+		code, err := colorCode(word, depth)
+		if err != nil {
+			return "", fmt.Errorf("color %q: %w", spec, err)
+		}
+		codes, hasColor = append(codes, code), true
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
+
+// colorCode returns the SGR parameters of one color.
+func colorCode(word string, depth ColorDepth) (string, error) {
+	switch {
+	case strings.HasPrefix(word, "#"):
+		r, g, b, err := parseHex(word)
+		if err != nil {
+			return "", err
+		}
+		return rgbCode(r, g, b, depth), nil
+	case word[0] >= '0' && word[0] <= '9':
+		n, err := strconv.Atoi(word)
+		if err != nil || n < 0 || n > 255 {
+			return "", fmt.Errorf("palette index %q is not between 0 and 255", word)
+		}
+		if depth == Color16 {
+			r, g, b := paletteRGB(n)
+			return strconv.Itoa(nearestBasic(r, g, b)), nil
+		}
+		return "38;5;" + word, nil
+	}
+	name, bright := word, false
+	for _, prefix := range []string{"bright-", "bright_", "light-", "light_"} {
+		if strings.HasPrefix(word, prefix) {
+			name, bright = strings.TrimPrefix(word, prefix), true
+		}
+	}
+	if name == "gray" || name == "grey" {
+		return "90", nil
+	}
+	code, ok := basicColors[name]
+	if !ok {
+		return "", fmt.Errorf("unknown color or attribute %q", word)
+	}
+	if bright {
+		code += 60
+	}
+	return strconv.Itoa(code), nil
+}
+
+// parseHex parses #rgb or #rrggbb.
+func parseHex(s string) (r, g, b int, err error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, perr := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || perr != nil {
+		return 0, 0, 0, fmt.Errorf("%q is not a #rgb or #rrggbb color", s)
+	}
+	return int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff), nil
+}
+
+// rgbCode returns the SGR parameters of an RGB color at depth.
+func rgbCode(r, g, b int, depth ColorDepth) string {
+	switch depth {
+	case TrueColor:
+		return fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	case Color256:
+		return "38;5;" + strconv.Itoa(nearest256(r, g, b))
+	default:
+		return strconv.Itoa(nearestBasic(r, g, b))
+	}
+}
+
+// cubeLevels are the channel values of the 6×6×6 color cube.
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// nearest256 returns the palette index closest to an RGB color, from the
+// color cube (16-231) or the gray ramp (232-255).
+func nearest256(r, g, b int) int {
+	level := func(v int) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if abs(v-l) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := level(r), level(g), level(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	gray := min(max((r+g+b)/3-8+5, 0)/10, 23)
+	gv := 8 + 10*gray
+	if distance(r, g, b, gv, gv, gv) < distance(r, g, b, cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]) {
+		return 232 + gray
+	}
+	return cube
+}
+
+// basicRGB approximates how terminals show the 16 basic colors.
+var basicRGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// nearestBasic returns the foreground code (30-37, 90-97) of the basic
+// color closest to an RGB color.
+func nearestBasic(r, g, b int) int {
+	best := 0
+	for i, c := range basicRGB {
+		if distance(r, g, b, c[0], c[1], c[2]) < distance(r, g, b, basicRGB[best][0], basicRGB[best][1], basicRGB[best][2]) {
+			best = i
+		}
+	}
+	if best < 8 {
+		return 30 + best
+	}
+	return 90 + best - 8
+}
+
+// paletteRGB returns the RGB value of a 256-color palette index.
+func paletteRGB(n int) (r, g, b int) {
+	switch {
+	case n < 16:
+		c := basicRGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	default:
+		v := 8 + 10*(n-232)
+		return v, v, v
+	}
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
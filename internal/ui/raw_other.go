@@ -0,0 +1,24 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package ui
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrRawUnsupported is returned where terminals cannot be put into raw mode.
+var ErrRawUnsupported = errors.New("raw terminal mode is not supported on this platform")
+
+// MakeRaw is not supported on this platform.
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	return nil, ErrRawUnsupported
+}
+
+// TerminalSize is not supported on this platform.
+func TerminalSize(f *os.File) (width, height int, err error) {
+	return 0, 0, ErrRawUnsupported
+}
+
+// NotifyResize does nothing on this platform.
+func NotifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scale colors values by magnitude. Each stop colors the values from its
+// threshold up to the next one; colors blend between stops unless Steps is
+// set. On 16-color terminals a stop uses its Basic theme role.
+type Scale struct {
+	Unit  string `yaml:"unit,omitempty"` // unit of the thresholds, e.g. celsius or kmh
+	Steps bool   `yaml:"steps,omitempty"`
+	Stops []Stop `yaml:"stops"`
+}
+
+// Stop is one threshold of a Scale.
+type Stop struct {
+	From  float64 `yaml:"from"`            // lowest value with this color
+	Color string  `yaml:"color"`           // #rrggbb, palette index or color name
+	Basic string  `yaml:"basic,omitempty"` // theme role on 16-color terminals, e.g. blue
+}
+
+// Scales are color scales by variable name.
+type Scales map[string]Scale
+
+// DefaultScales are the built-in scales: blue to red for temperature, green
+// to red for wind speed and UV index, light to dark blue for precipitation.
+func DefaultScales() Scales {
+	return Scales{
+		"temperature": {Unit: "celsius", Stops: []Stop{
+			{-20, "#3b4cc0", "blue"},
+			{0, "#6f9ef8", "cyan"},
+			{10, "#8fd16a", "green"},
+			{20, "#f6c15b", "yellow"},
+			{30, "#e0452c", "red"},
+			{38, "#a50026", "red"},
+		}},
+		"wind_speed": {Unit: "kmh", Stops: []Stop{
+			{0, "#66bd63", "green"},
+			{20, "#d9ef8b", "green"},
+			{40, "#fee08b", "yellow"},
+			{60, "#fc8d59", "yellow"},
+			{90, "#d73027", "red"},
+		}},
+		"precipitation": {Unit: "mm", Stops: []Stop{
+			{0, "#9e9e9e", "gray"},
+			{0.1, "#9ecae1", "cyan"},
+			{2, "#4292c6", "blue"},
+			{10, "#08519c", "blue"},
+			{30, "#6a51a3", "red"},
+		}},
+		"uv_index": {Steps: true, Stops: []Stop{
+			{0, "#4daf4a", "green"},
+			{3, "#ffd92f", "yellow"},
+			{6, "#ff7f00", "yellow"},
+			{8, "#e41a1c", "red"},
+			{11, "#984ea3", "red"},
+		}},
+	}
+}
+
+// Validate checks that the stops are in ascending order and their colors
+// and roles exist.
+func (s Scale) Validate() error {
+	if len(s.Stops) == 0 {
+		return fmt.Errorf("no stops")
+	}
+	for i, stop := range s.Stops {
+		if i > 0 && stop.From <= s.Stops[i-1].From {
+			return fmt.Errorf("stop %g is not above %g", stop.From, s.Stops[i-1].From)
+		}
+		if _, _, _, err := colorRGB(stop.Color); err != nil {
+			return fmt.Errorf("stop %g: %w", stop.From, err)
+		}
+		if _, ok := Roles[strings.ToLower(stop.Basic)]; stop.Basic != "" && !ok {
+			return fmt.Errorf("stop %g: unknown role %q", stop.From, stop.Basic)
+		}
+	}
+	return nil
+}
+
+// Color returns the escape sequence for v, or "" when theme has no colors
+// or v is NaN.
+func (s Scale) Color(v float64, theme Theme, depth ColorDepth) string {
+	if theme.Reset == "" || len(s.Stops) == 0 || math.IsNaN(v) {
+		return ""
+	}
+	i := sort.Search(len(s.Stops), func(i int) bool { return s.Stops[i].From > v }) - 1
+	i = max(i, 0)
+	stop := s.Stops[i]
+	if depth == Color16 && stop.Basic != "" {
+		if role, ok := Roles[strings.ToLower(stop.Basic)]; ok {
+			return *role(&theme)
+		}
+	}
+	r, g, b, err := colorRGB(stop.Color)
+	if err != nil {
+		return ""
+	}
+	if !s.Steps && i+1 < len(s.Stops) && v > stop.From {
+		next := s.Stops[i+1]
+		if r2, g2, b2, err := colorRGB(next.Color); err == nil {
+			t := (v - stop.From) / (next.From - stop.From)
+			r, g, b = blend(r, r2, t), blend(g, g2, t), blend(b, b2, t)
+		}
+	}
+	return "\033[" + rgbCode(r, g, b, depth) + "m"
+}
+
+// colorRGB returns the RGB value of a hex color, palette index or color name.
+func colorRGB(spec string) (r, g, b int, err error) {
+	word := strings.ToLower(strings.TrimSpace(spec))
+	if word == "" {
+		return 0, 0, 0, fmt.Errorf("missing color")
+	}
+	switch {
+	case strings.HasPrefix(word, "#"):
+		return parseHex(word)
+	case word[0] >= '0' && word[0] <= '9':
+		n, err := strconv.Atoi(word)
+		if err != nil || n < 0 || n > 255 {
+			return 0, 0, 0, fmt.Errorf("palette index %q is not between 0 and 255", word)
+		}
+		r, g, b := paletteRGB(n)
+		return r, g, b, nil
+	}
+	code, err := colorCode(word, Color16)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	n, _ := strconv.Atoi(code)
+	if n >= 90 {
+		n = n - 90 + 8 // bright colors follow the 8 basic ones
+	} else {
+		n -= 30
+	}
+	c := basicRGB[n]
+	return c[0], c[1], c[2], nil
+}
+
+func blend(a, b int, t float64) int {
+	return a + int(math.Round(float64(b-a)*t))
+}
@@ -0,0 +1,12 @@
+package ui
+
+import "os"
+
+// IsTerminal reports whether f is attached to a terminal rather than a pipe or file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeSpec is a user-defined theme: a built-in base theme and color specs
+// (see ParseColor) for some of its roles.
+type ThemeSpec struct {
+	Base   string            `yaml:"base,omitempty"` // dark (default), light or none
+	Colors map[string]string `yaml:",inline"`        // role name -> color spec
+}
+
+// Roles are the theme roles a ThemeSpec can set, by name.
+var Roles = map[string]func(*Theme) *string{
+	"bold":   func(t *Theme) *string { return &t.Bold },
+	"gray":   func(t *Theme) *string { return &t.Gray },
+	"cyan":   func(t *Theme) *string { return &t.Cyan },
+	"blue":   func(t *Theme) *string { return &t.Blue },
+	"green":  func(t *Theme) *string { return &t.Green },
+	"yellow": func(t *Theme) *string { return &t.Yellow },
+	"red":    func(t *Theme) *string { return &t.Red },
+}
+
+// Theme builds the theme with colors approximated at depth.
+func (s ThemeSpec) Theme(depth ColorDepth) (Theme, error) {
+	var t Theme
+	switch strings.ToLower(s.Base) {
+	case "", "dark":
+		t = DarkTheme
+	case "light":
+		t = LightTheme
+	case "none":
+		t = NoColor
+	default:
+		return Theme{}, fmt.Errorf("unknown base theme %q (want dark, light or none)", s.Base)
+	}
+	for _, role := range sortedKeys(s.Colors) {
+		field, ok := Roles[strings.ToLower(role)]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown role %q (want one of %s)", role, strings.Join(sortedKeys(Roles), ", "))
+		}
+		code, err := ParseColor(s.Colors[role], depth)
+		if err != nil {
+			return Theme{}, fmt.Errorf("role %s: %w", role, err)
+		}
+		*field(&t) = code
+	}
+	if t.Reset == "" {
+		t.Reset = "\033[0m"
+	}
+	return t, nil
+}
+
+// LoadThemeFile reads a ThemeSpec from a YAML file.
+func LoadThemeFile(path string) (ThemeSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ThemeSpec{}, err
+	}
+	var s ThemeSpec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return ThemeSpec{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// ResolveTheme returns the theme called name: a built-in theme (auto, dark,
+// light or none, see GetTheme), one of themes, or a theme file when name
+// ends in .yaml or .yml. Like auto, a user theme colors output only when
+// ColorEnabled(os.Stdout), unless force is set because it was asked for
+// explicitly.
+func ResolveTheme(name string, themes map[string]ThemeSpec, emojiOpt string, force bool) (Theme, error) {
+	switch strings.ToLower(name) {
+	case "", "auto", "dark", "light", "none":
+		return GetTheme(name, emojiOpt), nil
+	}
+	spec, ok := themes[name]
+	if !ok {
+		if ext := strings.ToLower(filepath.Ext(name)); ext != ".yaml" && ext != ".yml" {
+			return Theme{}, fmt.Errorf("unknown color theme %q (want auto, dark, light, none, a theme from the config or a .yaml file)", name)
+		}
+		var err error
+		if spec, err = LoadThemeFile(name); err != nil {
+			return Theme{}, err
+		}
+	}
+	t, err := spec.Theme(DetectColorDepth())
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+	if !force && !ColorEnabled(os.Stdout) {
+		return GetTheme("none", emojiOpt), nil
+	}
+	t.Emoji = GetTheme("none", emojiOpt).Emoji
+	return t, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
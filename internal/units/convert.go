@@ -0,0 +1,129 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"goweather/internal/model"
+)
+
+// Temperature converts a temperature between Celsius and Fahrenheit.
+func Temperature(v float64, from, to string) float64 {
+	if from == to {
+		return v
+	}
+	if from == Fahrenheit {
+		v = (v - 32) * 5 / 9
+	}
+	if to == Fahrenheit {
+		v = v*9/5 + 32
+	}
+	return v
+}
+
+// metres per second in one unit of each wind speed
+var windFactors = map[string]float64{
+	KMH:   1 / 3.6,
+	MS:    1,
+	MPH:   0.44704,
+	Knots: 0.514444,
+}
+
+// WindSpeed converts a wind speed between km/h, m/s, mph and knots.
+func WindSpeed(v float64, from, to string) float64 {
+	if from == to {
+		return v
+	}
+	return v * windFactors[from] / windFactors[to]
+}
+
+// Precipitation converts a precipitation amount between millimetres and inches.
+func Precipitation(v float64, from, to string) float64 {
+	if from == to {
+		return v
+	}
+	if from == Inch {
+		v *= 25.4
+	}
+	if to == Inch {
+		v /= 25.4
+	}
+	return v
+}
+
+// hectopascals in one unit of each pressure
+var pressureFactors = map[string]float64{
+	HPa:  1,
+	InHg: 33.8639,
+	MMHg: 1.33322,
+}
+
+// Pressure converts a pressure between hPa, inHg and mmHg.
+func Pressure(v float64, from, to string) float64 {
+	if from == to {
+		return v
+	}
+	return v * pressureFactors[from] / pressureFactors[to]
+}
+
+// ConvertCurrent converts w from the units it was fetched in to the target units.
+func ConvertCurrent(w *model.WeatherResponse, from, to Units) {
+	c := &w.Current
+	c.Temperature = Temperature(c.Temperature, from.Temperature, to.Temperature)
+	c.Windspeed = WindSpeed(c.Windspeed, from.WindSpeed, to.WindSpeed)
+	c.Pressure = Pressure(c.Pressure, from.Pressure, to.Pressure)
+	w.Units = to.Labels()
+}
+
+// ConvertHourly converts h from the units it was fetched in to the target units.
+func ConvertHourly(h *model.HourlyForecast, from, to Units) {
+	for i := range h.Hourly.Time {
+		h.Hourly.Temperature[i] = Temperature(h.Hourly.Temperature[i], from.Temperature, to.Temperature)
+		h.Hourly.Windspeed[i] = WindSpeed(h.Hourly.Windspeed[i], from.WindSpeed, to.WindSpeed)
+		h.Hourly.Pressure[i] = Pressure(h.Hourly.Pressure[i], from.Pressure, to.Pressure)
+	}
+	h.Units = to.Labels()
+}
+
+// ConvertDaily converts d from the units it was fetched in to the target units.
+func ConvertDaily(d *model.DailyForecast, from, to Units) {
+	for i := range d.Daily.Time {
+		d.Daily.TemperatureMax[i] = Temperature(d.Daily.TemperatureMax[i], from.Temperature, to.Temperature)
+		d.Daily.TemperatureMin[i] = Temperature(d.Daily.TemperatureMin[i], from.Temperature, to.Temperature)
+		d.Daily.PrecipitationSum[i] = Precipitation(d.Daily.PrecipitationSum[i], from.Precipitation, to.Precipitation)
+		d.Daily.WindspeedMax[i] = WindSpeed(d.Daily.WindspeedMax[i], from.WindSpeed, to.WindSpeed)
+	}
+	d.Units = to.Labels()
+}
+
+// Convert converts v between two units of the same quantity. Units are given
+// as identifiers ("kmh") or display labels ("km/h"), case-insensitive.
+func Convert(v float64, from, to string) (float64, error) {
+	groups := []struct {
+		units   []string
+		convert func(v float64, from, to string) float64
+	}{
+		{[]string{Celsius, Fahrenheit}, Temperature},
+		{[]string{KMH, MS, MPH, Knots}, WindSpeed},
+		{[]string{MM, Inch}, Precipitation},
+		{[]string{HPa, InHg, MMHg}, Pressure},
+	}
+	for _, g := range groups {
+		f, t := matchUnit(from, g.units), matchUnit(to, g.units)
+		if f != "" && t != "" {
+			return g.convert(v, f, t), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot convert %q to %q", from, to)
+}
+
+// matchUnit returns the identifier in ids that s names, or "".
+func matchUnit(s string, ids []string) string {
+	s = strings.TrimSpace(s)
+	for _, id := range ids {
+		if strings.EqualFold(s, id) || strings.EqualFold(s, Label(id)) {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"goweather/internal/model"
+)
+
+// Unit identifiers, matching Open-Meteo's temperature_unit, wind_speed_unit
+// and precipitation_unit parameters where Open-Meteo supports them.
+const (
+	Celsius    = "celsius"
+	Fahrenheit = "fahrenheit"
+
+	KMH   = "kmh"
+	MS    = "ms"
+	MPH   = "mph"
+	Knots = "kn"
+
+	MM   = "mm"
+	Inch = "inch"
+
+	HPa  = "hpa"
+	InHg = "inhg"
+	MMHg = "mmhg"
+)
+
+// Units selects the unit of every quantity goweather displays.
+type Units struct {
+	Temperature   string `yaml:"temperature,omitempty"`
+	WindSpeed     string `yaml:"wind_speed,omitempty"`
+	Precipitation string `yaml:"precipitation,omitempty"`
+	Pressure      string `yaml:"pressure,omitempty"`
+}
+
+// Predefined unit systems.
+var (
+	Metric   = Units{Temperature: Celsius, WindSpeed: KMH, Precipitation: MM, Pressure: HPa}
+	Imperial = Units{Temperature: Fahrenheit, WindSpeed: MPH, Precipitation: Inch, Pressure: InHg}
+)
+
+// System returns the unit system called name ("" selects metric).
+func System(name string) (Units, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "metric":
+		return Metric, nil
+	case "imperial", "us":
+		return Imperial, nil
+	default:
+		return Units{}, fmt.Errorf("unknown unit system %q (want metric or imperial)", name)
+	}
+}
+
+// With returns u with every non-empty field of override applied.
+func (u Units) With(override Units) Units {
+	if override.Temperature != "" {
+		u.Temperature = strings.ToLower(override.Temperature)
+	}
+	if override.WindSpeed != "" {
+		u.WindSpeed = strings.ToLower(override.WindSpeed)
+	}
+	if override.Precipitation != "" {
+		u.Precipitation = strings.ToLower(override.Precipitation)
+	}
+	if override.Pressure != "" {
+		u.Pressure = strings.ToLower(override.Pressure)
+	}
+	return u
+}
+
+// Validate reports the first unknown unit in u.
+func (u Units) Validate() error {
+	checks := []struct {
+		quantity, value string
+		allowed         []string
+	}{
+		{"temperature", u.Temperature, []string{Celsius, Fahrenheit}},
+		{"wind speed", u.WindSpeed, []string{KMH, MS, MPH, Knots}},
+		{"precipitation", u.Precipitation, []string{MM, Inch}},
+		{"pressure", u.Pressure, []string{HPa, InHg, MMHg}},
+	}
+	for _, c := range checks {
+		ok := false
+		for _, a := range c.allowed {
+			ok = ok || c.value == a
+		}
+		if !ok {
+			return fmt.Errorf("unknown %s unit %q (want %s)", c.quantity, c.value, strings.Join(c.allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// Key identifies the selection in cache keys.
+func (u Units) Key() string {
+	return u.Temperature + "-" + u.WindSpeed + "-" + u.Precipitation + "-" + u.Pressure
+}
+
+// Labels returns the display labels of the selection.
+func (u Units) Labels() model.Units {
+	return model.Units{
+		Temperature:   Label(u.Temperature),
+		WindSpeed:     Label(u.WindSpeed),
+		Precipitation: Label(u.Precipitation),
+		Pressure:      Label(u.Pressure),
+	}
+}
+
+// Label returns the display label of a unit identifier.
+func Label(unit string) string {
+	switch unit {
+	case Celsius:
+		return "°C"
+	case Fahrenheit:
+		return "°F"
+	case KMH:
+		return "km/h"
+	case MS:
+		return "m/s"
+	case MPH:
+		return "mph"
+	case Knots:
+		return "kn"
+	case MM:
+		return "mm"
+	case Inch:
+		return "in"
+	case HPa:
+		return "hPa"
+	case InHg:
+		return "inHg"
+	case MMHg:
+		return "mmHg"
+	default:
+		return unit
+	}
+}
+
+// Quantity is the physical kind of a weather variable, used to convert and
+// label variables that are not part of the fixed model fields.
+type Quantity int
+
+const (
+	QuantityNone Quantity = iota
+	QuantityTemperature
+	QuantityWindSpeed
+	QuantityPrecipitation
+	QuantityPressure
+	QuantityPercent
+	QuantityDistance // metres
+)
+
+// Convert converts v of this quantity between unit selections.
+func (q Quantity) Convert(v float64, from, to Units) float64 {
+	switch q {
+	case QuantityTemperature:
+		return Temperature(v, from.Temperature, to.Temperature)
+	case QuantityWindSpeed:
+		return WindSpeed(v, from.WindSpeed, to.WindSpeed)
+	case QuantityPrecipitation:
+		return Precipitation(v, from.Precipitation, to.Precipitation)
+	case QuantityPressure:
+		return Pressure(v, from.Pressure, to.Pressure)
+	default:
+		return v
+	}
+}
+
+// Label returns the display label of this quantity in the given units.
+func (q Quantity) Label(u Units) string {
+	switch q {
+	case QuantityTemperature:
+		return Label(u.Temperature)
+	case QuantityWindSpeed:
+		return Label(u.WindSpeed)
+	case QuantityPrecipitation:
+		return Label(u.Precipitation)
+	case QuantityPressure:
+		return Label(u.Pressure)
+	case QuantityPercent:
+		return "%"
+	case QuantityDistance:
+		return "m"
+	default:
+		return ""
+	}
+}